@@ -0,0 +1,110 @@
+package gitea
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+
+	"github.com/haborhuang/go-tools/clients/gitea/types"
+	clienttool "github.com/haborhuang/go-tools/http"
+)
+
+type Config struct {
+	Url string
+	// Access token of user
+	Token string
+}
+
+type Client struct {
+	url   *url.URL
+	token string
+}
+
+func NewClientOrDie(conf Config) *Client {
+	c, err := NewClient(conf)
+	if nil != err {
+		panic(fmt.Errorf("New client error: %v", err))
+	}
+
+	return c
+}
+
+func NewClient(conf Config) (*Client, error) {
+	u, err := url.Parse(conf.Url)
+	if nil != err {
+		return nil, fmt.Errorf("Parse url error: %v", err)
+	}
+
+	u.Path = "/api/v1"
+
+	return &Client{
+		url:   u,
+		token: conf.Token,
+	}, nil
+}
+
+func (c *Client) newRequest() *clienttool.HttpRequest {
+	return clienttool.NewHttpReq(*c.url).SetHeader("Authorization", "token "+c.token)
+}
+
+type response struct {
+	req *clienttool.HttpRequest
+}
+
+func (c *Client) newResponse(r *clienttool.HttpRequest) *response {
+	return &response{
+		req: r,
+	}
+}
+
+func (r *response) doRaw() (*http.Response, error) {
+	resp, err := r.req.DoRaw()
+	if nil != err {
+		return nil, err
+	}
+
+	if err := types.ParseErr(resp); nil != err {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *response) do() error {
+	resp, err := r.doRaw()
+	if nil != err {
+		return err
+	}
+
+	resp.Body.Close()
+	return nil
+}
+
+func (r *response) intoJson(expected interface{}) error {
+	resp, err := r.doRaw()
+	if nil != err {
+		return err
+	}
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	defer resp.Body.Close()
+
+	if err := json.Unmarshal(body, expected); nil != err {
+		return fmt.Errorf("Decode response error: %v\nBody:%s", err, string(body))
+	}
+
+	return nil
+}
+
+// attachmentName extracts file name from Content-Disposition header of response
+func attachmentName(resp *http.Response) (string, error) {
+	_, params, err := mime.ParseMediaType(resp.Header.Get("content-disposition"))
+	if nil != err {
+		return "", err
+	}
+
+	return params["filename"], nil
+}
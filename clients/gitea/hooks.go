@@ -0,0 +1,34 @@
+package gitea
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/haborhuang/go-tools/clients/gitea/types"
+)
+
+func hooksPath(owner, repo string) string {
+	return repoPath(owner, repo) + "/hooks"
+}
+
+func (c *Client) CreateHook(owner, repo string, hook *types.Hook) (*types.Hook, error) {
+	if nil == hook || hook.Config["url"] == "" {
+		return nil, fmt.Errorf("Missing url of hook")
+	}
+
+	var nh *types.Hook
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPost).RawSubPath(hooksPath(owner, repo)).JsonBody(hook),
+	).intoJson(&nh)
+
+	return nh, err
+}
+
+func (c *Client) ListHooks(owner, repo string) ([]*types.Hook, error) {
+	var res []*types.Hook
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(hooksPath(owner, repo)),
+	).intoJson(&res)
+
+	return res, err
+}
@@ -0,0 +1,167 @@
+package gitea
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/haborhuang/go-tools/clients/gitea/types"
+)
+
+const (
+	repoPathFmt     = "/repos/%s/%s"
+	treePathFmt     = repoPathFmt + "/git/trees/%s"
+	contentsPathFmt = repoPathFmt + "/contents/%s"
+	archivePathFmt  = repoPathFmt + "/archive/%s%s"
+	branchPathFmt   = repoPathFmt + "/branches/%s"
+)
+
+func repoPath(owner, repo string) string {
+	return fmt.Sprintf(repoPathFmt, url.PathEscape(owner), url.PathEscape(repo))
+}
+
+func treePath(owner, repo, sha string) string {
+	return fmt.Sprintf(treePathFmt, url.PathEscape(owner), url.PathEscape(repo), url.PathEscape(sha))
+}
+
+func contentsPath(owner, repo, fpath string) string {
+	segs := strings.Split(fpath, "/")
+	for i := range segs {
+		segs[i] = url.PathEscape(segs[i])
+	}
+	return fmt.Sprintf(contentsPathFmt, url.PathEscape(owner), url.PathEscape(repo), strings.Join(segs, "/"))
+}
+
+func branchPath(owner, repo, branch string) string {
+	return fmt.Sprintf(branchPathFmt, url.PathEscape(owner), url.PathEscape(repo), url.PathEscape(branch))
+}
+
+const (
+	ArchiveFormatTarGz = ".tar.gz"
+	ArchiveFormatZip   = ".zip"
+)
+
+func archivePath(owner, repo, ref, format string) string {
+	return fmt.Sprintf(archivePathFmt, url.PathEscape(owner), url.PathEscape(repo), url.PathEscape(ref), format)
+}
+
+func (c *Client) GetRepo(owner, repo string) (*types.Repository, error) {
+	var r *types.Repository
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(repoPath(owner, repo)),
+	).intoJson(&r)
+
+	return r, err
+}
+
+func (c *Client) GetBranch(owner, repo, branch string) (*types.Branch, error) {
+	var b *types.Branch
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(branchPath(owner, repo, branch)),
+	).intoJson(&b)
+
+	return b, err
+}
+
+// GetTree returns a page of tree of the commit, branch or tag specified by sha
+func (c *Client) GetTree(owner, repo, sha string, recursive bool, page, perPage int) (*types.Tree, error) {
+	q := make(url.Values)
+	if recursive {
+		q.Set("recursive", "true")
+	}
+	if page > 0 {
+		q.Set("page", strconv.Itoa(page))
+	}
+	if perPage > 0 {
+		q.Set("per_page", strconv.Itoa(perPage))
+	}
+
+	var t *types.Tree
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(treePath(owner, repo, sha)).Query(q),
+	).intoJson(&t)
+
+	return t, err
+}
+
+// ListContents lists entries of the directory at ref, or the default branch if ref is empty.
+// If dir is a file, the file itself is the only entry.
+func (c *Client) ListContents(owner, repo, dir, ref string) ([]*types.Contents, error) {
+	q := make(url.Values)
+	if ref != "" {
+		q.Set("ref", ref)
+	}
+
+	var raw json.RawMessage
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(contentsPath(owner, repo, dir)).Query(q),
+	).intoJson(&raw)
+	if nil != err {
+		return nil, err
+	}
+
+	// Gitea responds an object rather than an array for file
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '{' {
+		var file *types.Contents
+		if err := json.Unmarshal(trimmed, &file); nil != err {
+			return nil, fmt.Errorf("Decode response error: %v\nBody:%s", err, string(raw))
+		}
+		return []*types.Contents{file}, nil
+	}
+
+	var res []*types.Contents
+	if err := json.Unmarshal(raw, &res); nil != err {
+		return nil, fmt.Errorf("Decode response error: %v\nBody:%s", err, string(raw))
+	}
+
+	return res, nil
+}
+
+// GetContents returns metadata and base64 encoded content of file at ref, or the default branch if ref is empty
+func (c *Client) GetContents(owner, repo, fpath, ref string) (*types.Contents, error) {
+	q := make(url.Values)
+	if ref != "" {
+		q.Set("ref", ref)
+	}
+
+	var res *types.Contents
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(contentsPath(owner, repo, fpath)).Query(q),
+	).intoJson(&res)
+
+	return res, err
+}
+
+// ChangeFiles creates, updates or deletes files in one commit
+func (c *Client) ChangeFiles(owner, repo string, opts *types.ChangeFilesOpts) (*types.FilesResponse, error) {
+	var res *types.FilesResponse
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPost).RawSubPath(contentsPath(owner, repo, "")).JsonBody(opts),
+	).intoJson(&res)
+
+	return res, err
+}
+
+// RepoArchive returns file name and content of the archive in one of ArchiveFormat*.
+// The content should be closed by caller.
+func (c *Client) RepoArchive(owner, repo, ref, format string) (string, io.ReadCloser, error) {
+	resp, err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(archivePath(owner, repo, ref, format)),
+	).doRaw()
+	if nil != err {
+		return "", nil, err
+	}
+
+	name, err := attachmentName(resp)
+	if nil != err {
+		resp.Body.Close()
+		return "", nil, fmt.Errorf("Parse archive name error: %v", err)
+	}
+
+	return name, resp.Body, nil
+}
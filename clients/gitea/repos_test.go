@@ -0,0 +1,109 @@
+package gitea
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haborhuang/go-tools/clients/gitea/types"
+)
+
+func newTestClient(h http.HandlerFunc) (*Client, func()) {
+	s := httptest.NewServer(h)
+	return NewClientOrDie(Config{Url: s.URL, Token: "secret"}), s.Close
+}
+
+func TestListContents(t *testing.T) {
+	c, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.EscapedPath() {
+		case "/api/v1/repos/root/app/contents/docs":
+			json.NewEncoder(w).Encode([]*types.Contents{
+				{Name: "index.md", Path: "docs/index.md", Type: types.ContentsTypeFile},
+				{Name: "img", Path: "docs/img", Type: types.ContentsTypeDir},
+			})
+		case "/api/v1/repos/root/app/contents/docs/index.md":
+			if r.URL.Query().Get("ref") != "dev" {
+				t.Errorf("Unexpected ref '%s'", r.URL.Query().Get("ref"))
+			}
+			json.NewEncoder(w).Encode(&types.Contents{Name: "index.md", Path: "docs/index.md", Type: types.ContentsTypeFile})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"not found"}`))
+		}
+	})
+	defer done()
+
+	res, err := c.ListContents("root", "app", "docs", "")
+	if nil != err {
+		t.Fatalf("List directory error: %v", err)
+	}
+	if len(res) != 2 || res[1].Type != types.ContentsTypeDir {
+		t.Errorf("Unexpected contents %+v", res)
+	}
+
+	res, err = c.ListContents("root", "app", "docs/index.md", "dev")
+	if nil != err {
+		t.Fatalf("List file error: %v", err)
+	}
+	if len(res) != 1 || res[0].Path != "docs/index.md" {
+		t.Errorf("Unexpected contents %+v", res)
+	}
+
+	if _, err := c.ListContents("root", "app", "none", ""); !types.IsNotFoundErr(err) {
+		t.Errorf("Expect not found error but got %v", err)
+	}
+}
+
+func TestGetTree(t *testing.T) {
+	c, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v1/repos/root/app/git/trees/feature%2Fx" {
+			t.Errorf("Unexpected path '%s'", r.URL.EscapedPath())
+		}
+		q := r.URL.Query()
+		if q.Get("recursive") != "true" || q.Get("page") != "2" || q.Get("per_page") != "10" {
+			t.Errorf("Unexpected query '%s'", r.URL.RawQuery)
+		}
+		json.NewEncoder(w).Encode(&types.Tree{
+			Entries:    []*types.TreeEntry{{Path: "README.md", Type: types.TreeEntryTypeBlob}},
+			TotalCount: 11,
+		})
+	})
+	defer done()
+
+	tree, err := c.GetTree("root", "app", "feature/x", true, 2, 10)
+	if nil != err {
+		t.Fatalf("Get tree error: %v", err)
+	}
+	if len(tree.Entries) != 1 || tree.TotalCount != 11 {
+		t.Errorf("Unexpected tree %+v", tree)
+	}
+}
+
+func TestRepoArchive(t *testing.T) {
+	c, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v1/repos/root/app/archive/master.tar.gz" {
+			t.Errorf("Unexpected path '%s'", r.URL.EscapedPath())
+		}
+		w.Header().Set("Content-Disposition", `attachment; filename="app-master.tar.gz"`)
+		w.Write([]byte("archive"))
+	})
+	defer done()
+
+	name, rc, err := c.RepoArchive("root", "app", "master", ArchiveFormatTarGz)
+	if nil != err {
+		t.Fatalf("Get archive error: %v", err)
+	}
+	defer rc.Close()
+
+	content, _ := ioutil.ReadAll(rc)
+	if name != "app-master.tar.gz" || string(content) != "archive" {
+		t.Errorf("Unexpected archive '%s': %s", name, content)
+	}
+}
@@ -0,0 +1,45 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+type Error struct {
+	Status  int    `json:"-"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("(%d)%s", e.Status, e.Message)
+}
+
+func ParseErr(resp *http.Response) *Error {
+	if resp.StatusCode >= http.StatusBadRequest {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		var giteaErr Error
+		giteaErr.Status = resp.StatusCode
+		if len(msg) > 0 {
+			json.Unmarshal(msg, &giteaErr)
+			if giteaErr.Message == "" {
+				giteaErr.Message = string(msg)
+			}
+		}
+
+		return &giteaErr
+	}
+
+	return nil
+}
+
+func IsNotFoundErr(err error) bool {
+	if e, ok := err.(*Error); ok {
+		return e.Status == http.StatusNotFound
+	}
+
+	return false
+}
@@ -0,0 +1,21 @@
+package types
+
+import "time"
+
+const (
+	HookTypeGitea = "gitea"
+
+	HookEventPush        = "push"
+	HookEventCreate      = "create"
+	HookEventPullRequest = "pull_request"
+)
+
+type Hook struct {
+	Id        int64             `json:"id,omitempty"`
+	Type      string            `json:"type"`
+	Config    map[string]string `json:"config"`
+	Events    []string          `json:"events"`
+	Active    bool              `json:"active"`
+	CreatedAt *time.Time        `json:"created_at,omitempty"`
+	UpdatedAt *time.Time        `json:"updated_at,omitempty"`
+}
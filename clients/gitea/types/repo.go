@@ -0,0 +1,123 @@
+package types
+
+import "time"
+
+type User struct {
+	Id       int64  `json:"id"`
+	Login    string `json:"login"`
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+}
+
+type Repository struct {
+	Id            int64      `json:"id"`
+	Owner         *User      `json:"owner"`
+	Name          string     `json:"name"`
+	FullName      string     `json:"full_name"`
+	Description   string     `json:"description"`
+	Private       bool       `json:"private"`
+	Archived      bool       `json:"archived"`
+	DefaultBranch string     `json:"default_branch"`
+	HtmlUrl       string     `json:"html_url"`
+	CloneUrl      string     `json:"clone_url"`
+	SshUrl        string     `json:"ssh_url"`
+	CreatedAt     *time.Time `json:"created_at"`
+	UpdatedAt     *time.Time `json:"updated_at"`
+}
+
+const (
+	TreeEntryTypeBlob = "blob"
+	TreeEntryTypeTree = "tree"
+)
+
+type TreeEntry struct {
+	Path string `json:"path"`
+	Mode string `json:"mode"`
+	Type string `json:"type"`
+	Size int64  `json:"size"`
+	SHA  string `json:"sha"`
+}
+
+type Tree struct {
+	SHA        string       `json:"sha"`
+	Entries    []*TreeEntry `json:"tree"`
+	Truncated  bool         `json:"truncated"`
+	Page       int          `json:"page"`
+	TotalCount int          `json:"total_count"`
+}
+
+const (
+	ContentsTypeFile    = "file"
+	ContentsTypeDir     = "dir"
+	ContentsTypeSymlink = "symlink"
+)
+
+type Contents struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	SHA      string `json:"sha"`
+	Type     string `json:"type"`
+	Size     int64  `json:"size"`
+	Encoding string `json:"encoding,omitempty"`
+	Content  string `json:"content,omitempty"`
+}
+
+const (
+	FileOperationCreate = "create"
+	FileOperationUpdate = "update"
+	FileOperationDelete = "delete"
+)
+
+type Identity struct {
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+type ChangeFileOperation struct {
+	Operation string `json:"operation"`
+	Path      string `json:"path"`
+	// Base64 encoded content
+	Content string `json:"content,omitempty"`
+	// SHA of the file, required by update and delete
+	SHA string `json:"sha,omitempty"`
+	// Old path of the file to move, only used by update
+	FromPath string `json:"from_path,omitempty"`
+}
+
+type ChangeFilesOpts struct {
+	Files   []*ChangeFileOperation `json:"files"`
+	Message string                 `json:"message"`
+	Branch  string                 `json:"branch,omitempty"`
+	// Branch to be created from Branch for the commit
+	NewBranch string    `json:"new_branch,omitempty"`
+	Author    *Identity `json:"author,omitempty"`
+}
+
+type CommitMeta struct {
+	SHA     string     `json:"sha"`
+	Url     string     `json:"url"`
+	Created *time.Time `json:"created"`
+}
+
+type FileCommit struct {
+	CommitMeta
+	HtmlUrl string `json:"html_url"`
+	Message string `json:"message"`
+	Author  *struct {
+		Identity
+		Date string `json:"date"`
+	} `json:"author"`
+}
+
+type FilesResponse struct {
+	Commit *FileCommit `json:"commit"`
+}
+
+type Branch struct {
+	Name   string `json:"name"`
+	Commit struct {
+		Id      string `json:"id"`
+		Message string `json:"message"`
+	} `json:"commit"`
+	Protected bool `json:"protected"`
+}
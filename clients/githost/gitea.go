@@ -0,0 +1,261 @@
+package githost
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/haborhuang/go-tools/clients/gitea"
+	"github.com/haborhuang/go-tools/clients/gitea/types"
+)
+
+type giteaProvider struct {
+	c *gitea.Client
+}
+
+func NewGitea(c *gitea.Client) Provider {
+	return &giteaProvider{c: c}
+}
+
+func (p *giteaProvider) GetProject(projPath string) (*Project, error) {
+	owner, repo, err := splitPath(projPath)
+	if nil != err {
+		return nil, err
+	}
+
+	r, err := p.c.GetRepo(owner, repo)
+	if nil != err {
+		return nil, err
+	}
+
+	return &Project{
+		FullPath:      r.FullName,
+		Name:          r.Name,
+		Description:   r.Description,
+		DefaultBranch: r.DefaultBranch,
+		WebUrl:        r.HtmlUrl,
+		HttpCloneUrl:  r.CloneUrl,
+		SshCloneUrl:   r.SshUrl,
+	}, nil
+}
+
+func (p *giteaProvider) ListTree(projPath, dir, ref string, recursive bool) ([]*TreeEntry, error) {
+	owner, repo, err := splitPath(projPath)
+	if nil != err {
+		return nil, err
+	}
+
+	if !recursive {
+		contents, err := p.c.ListContents(owner, repo, dir, ref)
+		if nil != err {
+			return nil, err
+		}
+
+		res := make([]*TreeEntry, 0, len(contents))
+		for _, c := range contents {
+			entry := &TreeEntry{
+				Id:   c.SHA,
+				Name: c.Name,
+				Path: c.Path,
+				Type: TreeEntryTypeFile,
+			}
+			if c.Type == types.ContentsTypeDir {
+				entry.Type = TreeEntryTypeDir
+			}
+			res = append(res, entry)
+		}
+		return res, nil
+	}
+
+	if ref == "" {
+		if ref, err = p.defaultBranch(owner, repo); nil != err {
+			return nil, err
+		}
+	}
+
+	prefix := strings.Trim(dir, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	var res []*TreeEntry
+	for page, count := 1, 0; ; page++ {
+		tree, err := p.c.GetTree(owner, repo, ref, true, page, 1000)
+		if nil != err {
+			return nil, err
+		}
+
+		for _, e := range tree.Entries {
+			if !strings.HasPrefix(e.Path, prefix) {
+				continue
+			}
+
+			entry := &TreeEntry{
+				Id:   e.SHA,
+				Name: path.Base(e.Path),
+				Path: e.Path,
+				Type: TreeEntryTypeFile,
+			}
+			if e.Type == types.TreeEntryTypeTree {
+				entry.Type = TreeEntryTypeDir
+			}
+			res = append(res, entry)
+		}
+
+		count += len(tree.Entries)
+		if len(tree.Entries) == 0 || count >= tree.TotalCount {
+			return res, nil
+		}
+	}
+}
+
+func (p *giteaProvider) CreateCommit(projPath string, req *CommitRequest) (*Commit, error) {
+	owner, repo, err := splitPath(projPath)
+	if nil != err {
+		return nil, err
+	}
+
+	opts := &types.ChangeFilesOpts{
+		Message: req.Message,
+		Branch:  req.Branch,
+	}
+	if req.AuthorName != "" || req.AuthorEmail != "" {
+		opts.Author = &types.Identity{Name: req.AuthorName, Email: req.AuthorEmail}
+	}
+	if req.StartBranch != "" {
+		if _, err := p.c.GetBranch(owner, repo, req.Branch); types.IsNotFoundErr(err) {
+			opts.Branch = req.StartBranch
+			opts.NewBranch = req.Branch
+		} else if nil != err {
+			return nil, err
+		}
+	}
+
+	for _, a := range req.Actions {
+		op, err := p.fileOperation(owner, repo, opts.Branch, a)
+		if nil != err {
+			return nil, err
+		}
+		opts.Files = append(opts.Files, op)
+	}
+
+	res, err := p.c.ChangeFiles(owner, repo, opts)
+	if nil != err {
+		return nil, err
+	}
+
+	commit := &Commit{
+		Id:        res.Commit.SHA,
+		Message:   res.Commit.Message,
+		CreatedAt: res.Commit.Created,
+	}
+	if nil != res.Commit.Author {
+		commit.AuthorName = res.Commit.Author.Name
+		commit.AuthorEmail = res.Commit.Author.Email
+	}
+	return commit, nil
+}
+
+// fileOperation converts action into operation of Gitea, which requires SHA of the file to be changed
+func (p *giteaProvider) fileOperation(owner, repo, branch string, a *FileAction) (*types.ChangeFileOperation, error) {
+	op := &types.ChangeFileOperation{
+		Path:    a.Path,
+		Content: base64.StdEncoding.EncodeToString(a.Content),
+	}
+
+	switch a.Action {
+	case FileActionCreate:
+		op.Operation = types.FileOperationCreate
+		return op, nil
+	case FileActionUpdate, FileActionDelete:
+		op.Operation = types.FileOperationUpdate
+		if a.Action == FileActionDelete {
+			op.Operation = types.FileOperationDelete
+			op.Content = ""
+		}
+
+		old, err := p.c.GetContents(owner, repo, a.Path, branch)
+		if nil != err {
+			return nil, fmt.Errorf("Get file '%s' error: %v", a.Path, err)
+		}
+		op.SHA = old.SHA
+		return op, nil
+	case FileActionMove:
+		op.Operation = types.FileOperationUpdate
+		op.FromPath = a.PreviousPath
+
+		old, err := p.c.GetContents(owner, repo, a.PreviousPath, branch)
+		if nil != err {
+			return nil, fmt.Errorf("Get file '%s' error: %v", a.PreviousPath, err)
+		}
+		op.SHA = old.SHA
+		if nil == a.Content {
+			op.Content = old.Content
+		}
+		return op, nil
+	}
+
+	return nil, fmt.Errorf("Invalid action '%s'", a.Action)
+}
+
+func (p *giteaProvider) Archive(projPath, ref string) (string, io.ReadCloser, error) {
+	owner, repo, err := splitPath(projPath)
+	if nil != err {
+		return "", nil, err
+	}
+
+	if ref == "" {
+		if ref, err = p.defaultBranch(owner, repo); nil != err {
+			return "", nil, err
+		}
+	}
+
+	return p.c.RepoArchive(owner, repo, ref, gitea.ArchiveFormatTarGz)
+}
+
+func (p *giteaProvider) AddHook(projPath string, hook *Hook) (*Hook, error) {
+	owner, repo, err := splitPath(projPath)
+	if nil != err {
+		return nil, err
+	}
+
+	events := []string{types.HookEventPush}
+	if hook.TagPushEvents {
+		events = append(events, types.HookEventCreate)
+	}
+	if hook.MergeRequestEvents {
+		events = append(events, types.HookEventPullRequest)
+	}
+
+	h, err := p.c.CreateHook(owner, repo, &types.Hook{
+		Type: types.HookTypeGitea,
+		Config: map[string]string{
+			"url":          hook.Url,
+			"content_type": "json",
+			"secret":       hook.Secret,
+		},
+		Events: events,
+		Active: true,
+	})
+	if nil != err {
+		return nil, err
+	}
+
+	return &Hook{
+		Id:                 h.Id,
+		Url:                h.Config["url"],
+		Secret:             hook.Secret,
+		TagPushEvents:      hook.TagPushEvents,
+		MergeRequestEvents: hook.MergeRequestEvents,
+	}, nil
+}
+
+func (p *giteaProvider) defaultBranch(owner, repo string) (string, error) {
+	r, err := p.c.GetRepo(owner, repo)
+	if nil != err {
+		return "", err
+	}
+
+	return r.DefaultBranch, nil
+}
@@ -0,0 +1,133 @@
+package githost
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/haborhuang/go-tools/clients/gitea"
+	"github.com/haborhuang/go-tools/clients/gitea/types"
+)
+
+// newGiteaServer serves repository root/app with docs/index.md and README.md on branch master
+func newGiteaServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/repos/root/app", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&types.Repository{Name: "app", FullName: "root/app", DefaultBranch: "master"})
+	})
+	mux.HandleFunc("/api/v1/repos/root/app/contents/docs", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]*types.Contents{
+			{Name: "index.md", Path: "docs/index.md", SHA: "1", Type: types.ContentsTypeFile},
+		})
+	})
+	mux.HandleFunc("/api/v1/repos/root/app/contents/docs/index.md", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&types.Contents{Name: "index.md", Path: "docs/index.md", SHA: "1", Type: types.ContentsTypeFile})
+	})
+	mux.HandleFunc("/api/v1/repos/root/app/git/trees/master", func(w http.ResponseWriter, r *http.Request) {
+		// One entry per page
+		entries := []*types.TreeEntry{
+			{Path: "README.md", SHA: "0", Type: types.TreeEntryTypeBlob},
+			{Path: "docs", SHA: "2", Type: types.TreeEntryTypeTree},
+			{Path: "docs/index.md", SHA: "1", Type: types.TreeEntryTypeBlob},
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		tree := &types.Tree{TotalCount: len(entries)}
+		if page >= 1 && page <= len(entries) {
+			tree.Entries = entries[page-1 : page]
+		}
+		json.NewEncoder(w).Encode(tree)
+	})
+	mux.HandleFunc("/api/v1/repos/root/app/branches/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/root/app/branches/master" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"branch not found"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(&types.Branch{Name: "master"})
+	})
+	mux.HandleFunc("/api/v1/repos/root/app/contents", func(w http.ResponseWriter, r *http.Request) {
+		var opts types.ChangeFilesOpts
+		if err := json.NewDecoder(r.Body).Decode(&opts); nil != err {
+			t.Errorf("Decode body error: %v", err)
+		}
+		if opts.Branch != "master" || opts.NewBranch != "feature" {
+			t.Errorf("Unexpected branches '%s' and '%s'", opts.Branch, opts.NewBranch)
+		}
+		if len(opts.Files) != 1 || opts.Files[0].Operation != types.FileOperationUpdate || opts.Files[0].SHA != "1" {
+			t.Errorf("Unexpected files %+v", opts.Files)
+		}
+
+		res := &types.FilesResponse{Commit: &types.FileCommit{Message: opts.Message}}
+		res.Commit.SHA = "3"
+		json.NewEncoder(w).Encode(res)
+	})
+	mux.HandleFunc("/api/v1/repos/root/app/hooks", func(w http.ResponseWriter, r *http.Request) {
+		var h types.Hook
+		json.NewDecoder(r.Body).Decode(&h)
+		h.Id = 1
+		json.NewEncoder(w).Encode(&h)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestGiteaProvider(t *testing.T) {
+	s := newGiteaServer(t)
+	defer s.Close()
+	p := NewGitea(gitea.NewClientOrDie(gitea.Config{Url: s.URL}))
+
+	proj, err := p.GetProject("root/app")
+	if nil != err {
+		t.Fatalf("Get project error: %v", err)
+	}
+	if proj.DefaultBranch != "master" || proj.FullPath != "root/app" {
+		t.Errorf("Unexpected project %+v", proj)
+	}
+
+	for _, dir := range []string{"docs", "docs/index.md"} {
+		entries, err := p.ListTree("root/app", dir, "", false)
+		if nil != err {
+			t.Fatalf("List tree of '%s' error: %v", dir, err)
+		}
+		if len(entries) != 1 || entries[0].Path != "docs/index.md" || entries[0].Type != TreeEntryTypeFile {
+			t.Errorf("Unexpected entries of '%s' %+v", dir, entries)
+		}
+	}
+
+	entries, err := p.ListTree("root/app", "docs", "", true)
+	if nil != err {
+		t.Fatalf("List tree recursively error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Path != "docs/index.md" || entries[0].Name != "index.md" {
+		t.Errorf("Unexpected entries %+v", entries)
+	}
+
+	commit, err := p.CreateCommit("root/app", &CommitRequest{
+		Branch:      "feature",
+		StartBranch: "master",
+		Message:     "update",
+		Actions: []*FileAction{
+			{Action: FileActionUpdate, Path: "docs/index.md", Content: []byte("# app")},
+		},
+	})
+	if nil != err {
+		t.Fatalf("Create commit error: %v", err)
+	}
+	if commit.Id != "3" || commit.Message != "update" {
+		t.Errorf("Unexpected commit %+v", commit)
+	}
+
+	hook, err := p.AddHook("root/app", &Hook{Url: "http://ci/hook", TagPushEvents: true})
+	if nil != err {
+		t.Fatalf("Add hook error: %v", err)
+	}
+	if hook.Id != 1 || hook.Url != "http://ci/hook" || !hook.TagPushEvents {
+		t.Errorf("Unexpected hook %+v", hook)
+	}
+
+	if _, err := p.GetProject("invalid"); nil == err {
+		t.Errorf("Expect error of invalid path")
+	}
+}
@@ -0,0 +1,128 @@
+package githost
+
+import (
+	"encoding/base64"
+	"io"
+
+	"github.com/haborhuang/go-tools/clients/gitlab"
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+type gitlabProvider struct {
+	c gitlab.Interface
+}
+
+func NewGitLab(c gitlab.Interface) Provider {
+	return &gitlabProvider{c: c}
+}
+
+func (p *gitlabProvider) GetProject(projPath string) (*Project, error) {
+	proj, err := p.c.GetProject(types.UrlEncodedPath(projPath))
+	if nil != err {
+		return nil, err
+	}
+
+	return &Project{
+		FullPath:      proj.PathWithNamespace,
+		Name:          proj.Name,
+		Description:   proj.Description,
+		DefaultBranch: proj.DefaultBranch,
+		WebUrl:        proj.WebUrl,
+		HttpCloneUrl:  proj.HttpRepoUrl,
+		SshCloneUrl:   proj.SshRepoUrl,
+	}, nil
+}
+
+func (p *gitlabProvider) ListTree(projPath, dir, ref string, recursive bool) ([]*TreeEntry, error) {
+	var res []*TreeEntry
+	paging := &types.Pagination{Page: 1, PerPage: 100}
+	for {
+		objs, pages, err := p.c.RepoTree(types.UrlEncodedPath(projPath), dir, ref, recursive, paging)
+		if nil != err {
+			return nil, err
+		}
+
+		for _, obj := range objs {
+			entry := &TreeEntry{
+				Id:   obj.Id,
+				Name: obj.Name,
+				Path: obj.Path,
+				Type: TreeEntryTypeFile,
+			}
+			if obj.Type == types.TreeObjTypeTree {
+				entry.Type = TreeEntryTypeDir
+			}
+			res = append(res, entry)
+		}
+
+		if paging.Page >= pages {
+			return res, nil
+		}
+		paging.Page++
+	}
+}
+
+func (p *gitlabProvider) CreateCommit(projPath string, req *CommitRequest) (*Commit, error) {
+	payload := &types.CommitPayload{
+		CommitBasicInfo: types.CommitBasicInfo{
+			Branch:        req.Branch,
+			StartBranch:   req.StartBranch,
+			CommitMessage: req.Message,
+			AuthorName:    req.AuthorName,
+			AuthorEmail:   req.AuthorEmail,
+		},
+	}
+	for _, a := range req.Actions {
+		action := &types.CommitAction{
+			Action:       a.Action,
+			FilePath:     a.Path,
+			PreviousPath: a.PreviousPath,
+		}
+		if nil != a.Content {
+			action.Encoding = types.CommitContentEncodingBase64
+			action.Content = base64.StdEncoding.EncodeToString(a.Content)
+		}
+		payload.Actions = append(payload.Actions, action)
+	}
+
+	commit, err := p.c.CreateCommit(types.UrlEncodedPath(projPath), payload)
+	if nil != err {
+		return nil, err
+	}
+
+	return &Commit{
+		Id:          commit.Id,
+		Message:     commit.Message,
+		AuthorName:  commit.AuthorName,
+		AuthorEmail: commit.AuthorEmail,
+		CreatedAt:   &commit.CreatedAt,
+	}, nil
+}
+
+func (p *gitlabProvider) Archive(projPath, ref string) (string, io.ReadCloser, error) {
+	return p.c.RepoArchive(types.UrlEncodedPath(projPath), ref)
+}
+
+func (p *gitlabProvider) AddHook(projPath string, hook *Hook) (*Hook, error) {
+	h, err := p.c.AddProjectHook(types.UrlEncodedPath(projPath), &types.Hook{
+		Url:   hook.Url,
+		Token: hook.Secret,
+		HookFlags: types.HookFlags{
+			PushEvents:            true,
+			TagPushEvents:         hook.TagPushEvents,
+			MergeRequestsEvents:   hook.MergeRequestEvents,
+			EnableSSLVerification: true,
+		},
+	})
+	if nil != err {
+		return nil, err
+	}
+
+	return &Hook{
+		Id:                 int64(h.Id),
+		Url:                h.Url,
+		Secret:             hook.Secret,
+		TagPushEvents:      h.TagPushEvents,
+		MergeRequestEvents: h.MergeRequestsEvents,
+	}, nil
+}
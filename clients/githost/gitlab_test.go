@@ -0,0 +1,46 @@
+package githost
+
+import (
+	"testing"
+
+	"github.com/haborhuang/go-tools/clients/gitlab/gitlabtest"
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+func TestGitLabProvider(t *testing.T) {
+	s := gitlabtest.NewServer()
+	defer s.Close()
+	s.AddProject(&types.Project{Name: "app"})
+
+	p := NewGitLab(s.NewClient())
+	_, err := p.CreateCommit("root/app", &CommitRequest{
+		Branch:  "master",
+		Message: "init",
+		Actions: []*FileAction{
+			{Action: FileActionCreate, Path: "docs/index.md", Content: []byte("# app")},
+		},
+	})
+	if nil != err {
+		t.Fatalf("Create commit error: %v", err)
+	}
+
+	proj, err := p.GetProject("root/app")
+	if nil != err {
+		t.Fatalf("Get project error: %v", err)
+	}
+	if proj.DefaultBranch != "master" {
+		t.Errorf("Unexpected default branch '%s'", proj.DefaultBranch)
+	}
+
+	entries, err := p.ListTree("root/app", "docs", "", false)
+	if nil != err {
+		t.Fatalf("List tree error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Path != "docs/index.md" || entries[0].Type != TreeEntryTypeFile {
+		t.Errorf("Unexpected entries %+v", entries)
+	}
+
+	if _, err := p.GetProject("root/none"); !IsNotFoundErr(err) {
+		t.Errorf("Expect not found error but got %v", err)
+	}
+}
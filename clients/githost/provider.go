@@ -0,0 +1,39 @@
+// Package githost abstracts the operations of git hosting services, e.g. GitLab and Gitea,
+// so that tools can work with any of them.
+package githost
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	giteatypes "github.com/haborhuang/go-tools/clients/gitea/types"
+	gitlabtypes "github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+// Provider is implemented for each git hosting service.
+// Projects are specified by path with namespace, e.g. group/project.
+type Provider interface {
+	GetProject(projPath string) (*Project, error)
+	// ListTree lists entries of the directory at ref, or the default branch if ref is empty
+	ListTree(projPath, dir, ref string, recursive bool) ([]*TreeEntry, error)
+	CreateCommit(projPath string, req *CommitRequest) (*Commit, error)
+	// Archive returns file name and content of the tar.gz archive at ref. The content should be closed by caller.
+	Archive(projPath, ref string) (string, io.ReadCloser, error)
+	AddHook(projPath string, hook *Hook) (*Hook, error)
+}
+
+// IsNotFoundErr reports whether err is returned by provider for non-existent resource
+func IsNotFoundErr(err error) bool {
+	return gitlabtypes.IsNotFoundErr(err) || giteatypes.IsNotFoundErr(err)
+}
+
+// splitPath splits path with namespace into namespace and name
+func splitPath(projPath string) (string, string, error) {
+	i := strings.LastIndex(projPath, "/")
+	if i <= 0 || i == len(projPath)-1 {
+		return "", "", fmt.Errorf("Invalid project path '%s'", projPath)
+	}
+
+	return projPath[:i], projPath[i+1:], nil
+}
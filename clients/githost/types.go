@@ -0,0 +1,73 @@
+package githost
+
+import "time"
+
+type Project struct {
+	// Path of project with namespace, e.g. group/project for GitLab and owner/repo for Gitea
+	FullPath      string
+	Name          string
+	Description   string
+	DefaultBranch string
+	WebUrl        string
+	HttpCloneUrl  string
+	SshCloneUrl   string
+}
+
+const (
+	TreeEntryTypeDir  = "dir"
+	TreeEntryTypeFile = "file"
+)
+
+type TreeEntry struct {
+	// Object id of entry
+	Id   string
+	Name string
+	Path string
+	// One of TreeEntryType*
+	Type string
+}
+
+const (
+	FileActionCreate = "create"
+	FileActionUpdate = "update"
+	FileActionDelete = "delete"
+	FileActionMove   = "move"
+)
+
+type FileAction struct {
+	// One of FileAction*
+	Action string
+	Path   string
+	// Old path of the file to move
+	PreviousPath string
+	// Content of the file. Content of the moved file is kept if nil.
+	Content []byte
+}
+
+type CommitRequest struct {
+	Branch string
+	// Branch to create Branch from if it doesn't exist
+	StartBranch string
+	Message     string
+	AuthorName  string
+	AuthorEmail string
+	Actions     []*FileAction
+}
+
+type Commit struct {
+	Id          string
+	Message     string
+	AuthorName  string
+	AuthorEmail string
+	CreatedAt   *time.Time
+}
+
+type Hook struct {
+	Id  int64
+	Url string
+	// Secret to validate received payloads
+	Secret string
+	// Push events are always enabled
+	TagPushEvents      bool
+	MergeRequestEvents bool
+}
@@ -0,0 +1,301 @@
+package gitlab
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExtractArchive extracts the archive returned by RepoArchiveWithOpts into dest directory.
+// The format is detected by extension of name. The leading strip components of paths are removed,
+// e.g. 1 to remove the top directory <project>-<sha> created by GitLab.
+// Entries which would be written outside of dest are rejected, as well as dangling links.
+func ExtractArchive(name string, r io.Reader, dest string, strip int) error {
+	dest, err := filepath.Abs(dest)
+	if nil != err {
+		return err
+	}
+	if err := os.MkdirAll(dest, 0755); nil != err {
+		return fmt.Errorf("Create directory error: %v", err)
+	}
+	// Links within dest are resolved against the real path of dest
+	if dest, err = filepath.EvalSymlinks(dest); nil != err {
+		return fmt.Errorf("Resolve directory error: %v", err)
+	}
+
+	var links []string
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		var gr *gzip.Reader
+		if gr, err = gzip.NewReader(r); nil != err {
+			return fmt.Errorf("Read gzip error: %v", err)
+		}
+		defer gr.Close()
+		links, err = extractTar(gr, dest, strip)
+	case strings.HasSuffix(name, ".tar.bz2"), strings.HasSuffix(name, ".tbz2"):
+		links, err = extractTar(bzip2.NewReader(r), dest, strip)
+	case strings.HasSuffix(name, ".tar"):
+		links, err = extractTar(r, dest, strip)
+	case strings.HasSuffix(name, ".zip"):
+		links, err = extractZip(r, dest, strip)
+	default:
+		return fmt.Errorf("Unknown archive format of '%s'", name)
+	}
+	if nil != err {
+		return err
+	}
+
+	return checkLinks(dest, links)
+}
+
+// checkLinks removes the first link which is dangling or resolved to outside of dest and returns error.
+// Links are checked after all of them are extracted since a link may be changed by the later ones,
+// e.g. a -> x/x/.. is within dest until x -> . is extracted.
+func checkLinks(dest string, links []string) error {
+	for _, link := range links {
+		resolved, err := filepath.EvalSymlinks(link)
+		if nil == err && withinDir(dest, resolved) {
+			continue
+		}
+
+		if err := removeLink(link); nil != err {
+			return err
+		}
+		return fmt.Errorf("Illegal link '%s' in archive", link)
+	}
+
+	return nil
+}
+
+// targetPath returns the path to extract entry to, or empty string if the entry is stripped.
+// Error is returned if the path is outside of dest, either directly or through links extracted before.
+func targetPath(dest, entry string, strip int) (string, error) {
+	parts := strings.Split(strings.Trim(filepath.ToSlash(entry), "/"), "/")
+	if len(parts) <= strip {
+		return "", nil
+	}
+
+	target := filepath.Join(dest, filepath.FromSlash(strings.Join(parts[strip:], "/")))
+	if !withinDir(dest, target) {
+		return "", fmt.Errorf("Illegal path '%s' in archive", entry)
+	}
+	if err := checkParents(dest, target); nil != err {
+		return "", err
+	}
+
+	return target, nil
+}
+
+// checkParents returns error if any existing parent of target is a link resolved to outside of dest
+func checkParents(dest, target string) error {
+	rel, err := filepath.Rel(dest, filepath.Dir(target))
+	if nil != err || rel == "." {
+		return err
+	}
+
+	p := dest
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		p = filepath.Join(p, part)
+		fi, err := os.Lstat(p)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if nil != err {
+			return fmt.Errorf("Stat '%s' error: %v", p, err)
+		}
+		if fi.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+
+		resolved, err := filepath.EvalSymlinks(p)
+		if nil != err || !withinDir(dest, resolved) {
+			return fmt.Errorf("Illegal path '%s' through link '%s' in archive", target, p)
+		}
+	}
+
+	return nil
+}
+
+func withinDir(dir, target string) bool {
+	rel, err := filepath.Rel(dir, target)
+	return nil == err && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// extractTar returns the extracted links
+func extractTar(r io.Reader, dest string, strip int) ([]string, error) {
+	var links []string
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return links, nil
+		}
+		if nil != err {
+			return nil, fmt.Errorf("Read tar error: %v", err)
+		}
+
+		target, err := targetPath(dest, hdr.Name, strip)
+		if nil != err {
+			return nil, err
+		}
+		if target == "" {
+			continue
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0755)
+		case tar.TypeReg:
+			err = writeFile(target, tr, hdr.FileInfo().Mode())
+		case tar.TypeSymlink:
+			err = symlink(dest, target, hdr.Linkname)
+			links = append(links, target)
+		default:
+			// Skip other types, e.g. pax global header of git archive
+		}
+		if nil != err {
+			return nil, err
+		}
+	}
+}
+
+// extractZip returns the extracted links
+func extractZip(r io.Reader, dest string, strip int) ([]string, error) {
+	// Zip requires random access, so save it into a temporary file
+	tmp, err := ioutil.TempFile("", "gitlab-archive-")
+	if nil != err {
+		return nil, fmt.Errorf("Create temporary file error: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, r)
+	if nil != err {
+		return nil, fmt.Errorf("Save archive error: %v", err)
+	}
+
+	zr, err := zip.NewReader(tmp, size)
+	if nil != err {
+		return nil, fmt.Errorf("Read zip error: %v", err)
+	}
+
+	var links []string
+	for _, f := range zr.File {
+		target, err := targetPath(dest, f.Name, strip)
+		if nil != err {
+			return nil, err
+		}
+		if target == "" {
+			continue
+		}
+
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			err = os.MkdirAll(target, 0755)
+		case mode&os.ModeSymlink != 0:
+			err = extractZipSymlink(dest, target, f)
+			links = append(links, target)
+		default:
+			err = extractZipFile(target, f)
+		}
+		if nil != err {
+			return nil, err
+		}
+	}
+
+	return links, nil
+}
+
+func extractZipFile(target string, f *zip.File) error {
+	rc, err := f.Open()
+	if nil != err {
+		return fmt.Errorf("Read '%s' error: %v", f.Name, err)
+	}
+	defer rc.Close()
+
+	return writeFile(target, rc, f.Mode())
+}
+
+func extractZipSymlink(dest, target string, f *zip.File) error {
+	rc, err := f.Open()
+	if nil != err {
+		return fmt.Errorf("Read '%s' error: %v", f.Name, err)
+	}
+	defer rc.Close()
+
+	link, err := ioutil.ReadAll(rc)
+	if nil != err {
+		return fmt.Errorf("Read '%s' error: %v", f.Name, err)
+	}
+
+	return symlink(dest, target, string(link))
+}
+
+func writeFile(target string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); nil != err {
+		return fmt.Errorf("Create directory error: %v", err)
+	}
+	// Replace rather than write through the existing link
+	if err := removeLink(target); nil != err {
+		return err
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm()|0600)
+	if nil != err {
+		return fmt.Errorf("Create file error: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); nil != err {
+		return fmt.Errorf("Write file '%s' error: %v", target, err)
+	}
+
+	return nil
+}
+
+// symlink creates the link only if it points to somewhere within dest.
+// Relative link is resolved against the real directory of target, which may be reached through other links.
+// Links may still escape through the later ones, which is checked by checkLinks.
+func symlink(dest, target, link string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); nil != err {
+		return fmt.Errorf("Create directory error: %v", err)
+	}
+
+	resolved := link
+	if !filepath.IsAbs(link) {
+		dir, err := filepath.EvalSymlinks(filepath.Dir(target))
+		if nil != err {
+			return fmt.Errorf("Resolve directory error: %v", err)
+		}
+		resolved = filepath.Join(dir, link)
+	}
+	if !withinDir(dest, resolved) {
+		return fmt.Errorf("Illegal link '%s' to '%s' in archive", target, link)
+	}
+
+	if err := removeLink(target); nil != err {
+		return err
+	}
+	return os.Symlink(link, target)
+}
+
+// removeLink removes target if it is an existing link
+func removeLink(target string) error {
+	fi, err := os.Lstat(target)
+	if nil != err || fi.Mode()&os.ModeSymlink == 0 {
+		return nil
+	}
+
+	if err := os.Remove(target); nil != err {
+		return fmt.Errorf("Remove link '%s' error: %v", target, err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package gitlab_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/haborhuang/go-tools/clients/gitlab"
+	"github.com/haborhuang/go-tools/clients/gitlab/gitlabtest"
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+func TestExtractArchive(t *testing.T) {
+	s := gitlabtest.NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	p := s.AddProject(&types.Project{Name: "app"})
+	pid := types.UrlEncodedPath(p.PathWithNamespace)
+	_, err := c.CreateCommit(pid, &types.CommitPayload{
+		CommitBasicInfo: types.CommitBasicInfo{Branch: "master", CommitMessage: "init"},
+		Actions: []*types.CommitAction{
+			{Action: types.CommitActionCreate, FilePath: "README.md", CommitContent: types.CommitContent{Content: "app"}},
+			{Action: types.CommitActionCreate, FilePath: "deploy/k8s/app.yaml", CommitContent: types.CommitContent{Content: "kind: Pod"}},
+		},
+	})
+	if nil != err {
+		t.Fatalf("Create commit error: %v", err)
+	}
+
+	for _, format := range []string{types.ArchiveFormatTarGz, types.ArchiveFormatTar, types.ArchiveFormatZip} {
+		name, rc, err := c.RepoArchiveWithOpts(pid, &types.ArchiveOpts{Format: format, Path: "deploy"})
+		if nil != err {
+			t.Fatalf("Get %s archive error: %v", format, err)
+		}
+
+		dir, _ := ioutil.TempDir("", "extract-")
+		defer os.RemoveAll(dir)
+		err = gitlab.ExtractArchive(name, rc, dir, 1)
+		rc.Close()
+		if nil != err {
+			t.Fatalf("Extract %s error: %v", name, err)
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, "deploy", "k8s", "app.yaml"))
+		if nil != err || string(data) != "kind: Pod" {
+			t.Errorf("Unexpected content extracted from %s: %s, %v", name, data, err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "README.md")); !os.IsNotExist(err) {
+			t.Errorf("Unexpected file out of path in %s", name)
+		}
+	}
+}
+
+func TestExtractArchiveTraversal(t *testing.T) {
+	for _, hdrs := range [][]*tar.Header{
+		{{Name: "top/../../evil", Typeflag: tar.TypeReg}},
+		{{Name: "top/link", Typeflag: tar.TypeSymlink, Linkname: "../../etc"}},
+		// Chained links which escape only when resolved against the real directory
+		{
+			{Name: "top/l", Typeflag: tar.TypeSymlink, Linkname: "."},
+			{Name: "top/l/x", Typeflag: tar.TypeSymlink, Linkname: "../escaped"},
+			{Name: "top/x/pwned", Typeflag: tar.TypeReg},
+		},
+		// Link which escapes through the link extracted before it
+		{
+			{Name: "top/x", Typeflag: tar.TypeSymlink, Linkname: "."},
+			{Name: "top/a", Typeflag: tar.TypeSymlink, Linkname: "x/x/.."},
+		},
+		// Link which escapes through the link extracted after it
+		{
+			{Name: "top/a", Typeflag: tar.TypeSymlink, Linkname: "x/x/.."},
+			{Name: "top/x", Typeflag: tar.TypeSymlink, Linkname: "."},
+		},
+	} {
+		buf := bytes.NewBuffer(nil)
+		tw := tar.NewWriter(buf)
+		for _, hdr := range hdrs {
+			tw.WriteHeader(hdr)
+		}
+		tw.Close()
+
+		parent, _ := ioutil.TempDir("", "extract-")
+		defer os.RemoveAll(parent)
+		dir := filepath.Join(parent, "dest")
+		os.Mkdir(filepath.Join(parent, "escaped"), 0755)
+		last := hdrs[len(hdrs)-1].Name
+		if err := gitlab.ExtractArchive("a.tar", buf, dir, 1); nil == err {
+			t.Errorf("Expect error on extracting %s", last)
+		}
+		if _, err := os.Lstat(filepath.Join(parent, "escaped", "pwned")); nil == err {
+			t.Errorf("Unexpected file outside of dest extracted from %s", last)
+		}
+		if resolved, err := filepath.EvalSymlinks(filepath.Join(dir, "a")); nil == err && resolved == parent {
+			t.Errorf("Unexpected link to outside of dest extracted from %s", last)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+	clienttool "github.com/haborhuang/go-tools/http"
+)
+
+const (
+	// Personal, project or group access token sent as PRIVATE-TOKEN
+	AuthModePrivateToken = "private_token"
+	// OAuth2 access token sent as bearer token
+	AuthModeOAuth2 = "oauth2"
+	// CI job token, i.e. $CI_JOB_TOKEN, sent as JOB-TOKEN
+	AuthModeJobToken = "job_token"
+	// Deploy token sent as DEPLOY-TOKEN, only accepted by registry APIs
+	AuthModeDeployToken = "deploy_token"
+)
+
+// OAuth2Config is used to refresh the OAuth2 access token in Config.Token
+type OAuth2Config struct {
+	ClientId     string
+	ClientSecret string
+	RedirectUri  string
+	RefreshToken string
+	// Expiry time of the access token. The token is never refreshed if zero.
+	ExpiresAt time.Time
+	// Called with the new token after each refresh, e.g. to persist it
+	OnRefresh func(token *types.OAuth2Token)
+}
+
+// authenticator sets credentials on requests
+type authenticator interface {
+	authenticate(r *clienttool.HttpRequest) *clienttool.HttpRequest
+	// unauthorized is called with the request rejected by 401
+	unauthorized(r *http.Request)
+}
+
+func newAuthenticator(u url.URL, conf Config) (authenticator, error) {
+	switch conf.AuthMode {
+	case "", AuthModePrivateToken:
+		return headerAuth{key: "PRIVATE-TOKEN", value: conf.Token}, nil
+	case AuthModeJobToken:
+		return headerAuth{key: "JOB-TOKEN", value: conf.Token}, nil
+	case AuthModeDeployToken:
+		return headerAuth{key: "DEPLOY-TOKEN", value: conf.Token}, nil
+	case AuthModeOAuth2:
+		a := &oauth2Auth{
+			accessToken: conf.Token,
+		}
+		if nil != conf.OAuth2 {
+			a.conf = *conf.OAuth2
+			a.expiresAt = conf.OAuth2.ExpiresAt
+			a.refreshToken = conf.OAuth2.RefreshToken
+		}
+		u.Path = "/oauth/token"
+		a.tokenUrl = u
+		return a, nil
+	}
+
+	return nil, fmt.Errorf("Unknown auth mode '%s'", conf.AuthMode)
+}
+
+type headerAuth struct {
+	key   string
+	value string
+}
+
+func (a headerAuth) authenticate(r *clienttool.HttpRequest) *clienttool.HttpRequest {
+	return r.SetHeader(a.key, a.value)
+}
+
+func (a headerAuth) unauthorized(r *http.Request) {}
+
+// Refresh access token this long before it expires
+const oauth2RefreshAhead = 30 * time.Second
+
+type oauth2Auth struct {
+	conf     OAuth2Config
+	tokenUrl url.URL
+
+	lock         sync.Mutex
+	accessToken  string
+	refreshToken string
+	expiresAt    time.Time
+	// Refresh in flight, shared by all callers waiting for the new token
+	refreshing *oauth2Refresh
+}
+
+type oauth2Refresh struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+func (a *oauth2Auth) authenticate(r *clienttool.HttpRequest) *clienttool.HttpRequest {
+	token, err := a.token()
+	if nil != err {
+		return r.SetError(fmt.Errorf("Refresh OAuth2 token error: %v", err))
+	}
+
+	return r.SetHeader("Authorization", "Bearer "+token)
+}
+
+// unauthorized marks the access token sent by r as expired so that the next request refreshes it.
+// The token is kept if it has been refreshed already, thus concurrent 401s trigger only one refresh.
+func (a *oauth2Auth) unauthorized(r *http.Request) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.refreshToken == "" || r.Header.Get("Authorization") != "Bearer "+a.accessToken {
+		return
+	}
+	if a.expiresAt.IsZero() || time.Now().Before(a.expiresAt) {
+		a.expiresAt = time.Now()
+	}
+}
+
+// token returns the access token, refreshing it if it is about to expire.
+// Concurrent callers share a single refresh and the lock is not held during the request.
+func (a *oauth2Auth) token() (string, error) {
+	a.lock.Lock()
+	if a.expiresAt.IsZero() || time.Now().Add(oauth2RefreshAhead).Before(a.expiresAt) {
+		token := a.accessToken
+		a.lock.Unlock()
+		return token, nil
+	}
+	if nil != a.refreshing {
+		r := a.refreshing
+		a.lock.Unlock()
+		<-r.done
+		return r.token, r.err
+	}
+	if a.refreshToken == "" {
+		a.lock.Unlock()
+		return "", fmt.Errorf("Access token expired without refresh token")
+	}
+
+	r := &oauth2Refresh{done: make(chan struct{})}
+	a.refreshing = r
+	refreshToken := a.refreshToken
+	a.lock.Unlock()
+
+	token, err := a.refresh(refreshToken)
+
+	a.lock.Lock()
+	if nil == err {
+		a.accessToken = token.AccessToken
+		a.expiresAt = token.ExpiresAt()
+		if token.RefreshToken != "" {
+			a.refreshToken = token.RefreshToken
+		}
+		r.token = token.AccessToken
+	}
+	r.err = err
+	a.refreshing = nil
+	a.lock.Unlock()
+	close(r.done)
+
+	if nil != err {
+		return "", err
+	}
+	if nil != a.conf.OnRefresh {
+		a.conf.OnRefresh(token)
+	}
+
+	return r.token, nil
+}
+
+// refresh requests a new access token by refresh token
+func (a *oauth2Auth) refresh(refreshToken string) (*types.OAuth2Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+	form.Set("client_id", a.conf.ClientId)
+	form.Set("client_secret", a.conf.ClientSecret)
+	if a.conf.RedirectUri != "" {
+		form.Set("redirect_uri", a.conf.RedirectUri)
+	}
+
+	var token *types.OAuth2Token
+	err := (&response{
+		req: clienttool.NewHttpReq(a.tokenUrl).Method(http.MethodPost).FormBody(form),
+	}).intoJson(&token)
+	if nil != err {
+		return nil, err
+	}
+
+	return token, nil
+}
@@ -0,0 +1,156 @@
+package gitlab_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/haborhuang/go-tools/clients/gitlab"
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+func TestAuthModes(t *testing.T) {
+	cases := []struct {
+		mode   string
+		header string
+		value  string
+	}{
+		{"", "PRIVATE-TOKEN", "secret"},
+		{gitlab.AuthModePrivateToken, "PRIVATE-TOKEN", "secret"},
+		{gitlab.AuthModeJobToken, "JOB-TOKEN", "secret"},
+		{gitlab.AuthModeDeployToken, "DEPLOY-TOKEN", "secret"},
+		{gitlab.AuthModeOAuth2, "Authorization", "Bearer secret"},
+	}
+
+	for _, tc := range cases {
+		var got http.Header
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.Header
+			json.NewEncoder(w).Encode(&types.Project{Id: 1})
+		}))
+		c := gitlab.NewClientOrDie(gitlab.Config{Url: s.URL, Token: "secret", AuthMode: tc.mode})
+		_, err := c.GetProject("1")
+		s.Close()
+		if nil != err {
+			t.Fatalf("Get project in mode '%s' error: %v", tc.mode, err)
+		}
+		if got.Get(tc.header) != tc.value {
+			t.Errorf("Expect %s '%s' in mode '%s', got headers %v", tc.header, tc.value, tc.mode, got)
+		}
+	}
+
+	if _, err := gitlab.NewClient(gitlab.Config{AuthMode: "unknown"}); nil == err {
+		t.Errorf("Expect error of unknown auth mode")
+	}
+}
+
+// newOAuth2Server accepts access token "new" only, which is returned by refreshing token "refresh"
+func newOAuth2Server(t *testing.T, refreshes *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/token" {
+			atomic.AddInt32(refreshes, 1)
+			if r.FormValue("grant_type") != "refresh_token" || r.FormValue("refresh_token") != "refresh" {
+				t.Errorf("Unexpected refresh form %v", r.Form)
+			}
+			// Let concurrent requests wait for the refresh in flight
+			time.Sleep(20 * time.Millisecond)
+			json.NewEncoder(w).Encode(&types.OAuth2Token{AccessToken: "new", RefreshToken: "refresh2", ExpiresIn: 3600})
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer new" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"401 Unauthorized"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(&types.Project{Id: 1})
+	}))
+}
+
+// getProjects gets project concurrently and returns the number of errors
+func getProjects(c *gitlab.Client, n int) int {
+	var (
+		wg   sync.WaitGroup
+		errs int32
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetProject("1"); nil != err {
+				atomic.AddInt32(&errs, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return int(errs)
+}
+
+func TestOAuth2RefreshExpired(t *testing.T) {
+	var refreshes int32
+	s := newOAuth2Server(t, &refreshes)
+	defer s.Close()
+
+	var refreshed *types.OAuth2Token
+	c := gitlab.NewClientOrDie(gitlab.Config{
+		Url:      s.URL,
+		Token:    "old",
+		AuthMode: gitlab.AuthModeOAuth2,
+		OAuth2: &gitlab.OAuth2Config{
+			RefreshToken: "refresh",
+			ExpiresAt:    time.Now().Add(-time.Minute),
+			OnRefresh:    func(token *types.OAuth2Token) { refreshed = token },
+		},
+	})
+
+	if errs := getProjects(c, 10); errs != 0 {
+		t.Fatalf("Expect no errors, got %d", errs)
+	}
+	if refreshes != 1 {
+		t.Fatalf("Expect 1 refresh, got %d", refreshes)
+	}
+	if nil == refreshed || refreshed.RefreshToken != "refresh2" {
+		t.Errorf("Unexpected refreshed token %+v", refreshed)
+	}
+}
+
+func TestOAuth2RefreshUnauthorized(t *testing.T) {
+	var refreshes int32
+	s := newOAuth2Server(t, &refreshes)
+	defer s.Close()
+
+	// The token never expires by config, but is revoked by server
+	c := gitlab.NewClientOrDie(gitlab.Config{
+		Url:      s.URL,
+		Token:    "old",
+		AuthMode: gitlab.AuthModeOAuth2,
+		OAuth2:   &gitlab.OAuth2Config{RefreshToken: "refresh"},
+	})
+
+	if errs := getProjects(c, 10); errs != 10 {
+		t.Fatalf("Expect 10 errors of revoked token, got %d", errs)
+	}
+	if errs := getProjects(c, 10); errs != 0 {
+		t.Fatalf("Expect no errors after 401s, got %d", errs)
+	}
+	if refreshes != 1 {
+		t.Fatalf("Expect 1 refresh after concurrent 401s, got %d", refreshes)
+	}
+
+	// Without refresh token the 401 is returned as is
+	c = gitlab.NewClientOrDie(gitlab.Config{Url: s.URL, Token: "old", AuthMode: gitlab.AuthModeOAuth2})
+	for i := 0; i < 2; i++ {
+		_, err := c.GetProject("1")
+		if e, ok := err.(*types.Error); !ok || e.Status != http.StatusUnauthorized {
+			t.Fatalf("Expect unauthorized error, got %v", err)
+		}
+	}
+	if refreshes != 1 {
+		t.Errorf("Expect no more refresh, got %d", refreshes)
+	}
+}
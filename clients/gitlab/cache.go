@@ -0,0 +1,290 @@
+package gitlab
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+// CachedClient decorates Interface with caching of GetProject and GetGroup.
+// Cached objects are invalidated after TTL or once they are changed via CachedClient.
+// Changes made by others are not visible until the cached objects expire.
+type CachedClient struct {
+	Interface
+	projects *ttlCache
+	groups   *ttlCache
+}
+
+func NewCachedClient(c Interface, ttl time.Duration) *CachedClient {
+	return &CachedClient{
+		Interface: c,
+		projects:  newTTLCache(ttl),
+		groups:    newTTLCache(ttl),
+	}
+}
+
+func (c *CachedClient) GetProject(projId string) (*types.Project, error) {
+	if obj, ok := c.projects.get(projId); ok {
+		return copyProject(obj.(*types.Project)), nil
+	}
+
+	p, err := c.Interface.GetProject(projId)
+	if nil != err {
+		return nil, err
+	}
+
+	c.projects.set(p.Id, copyProject(p), projId, p.PathWithNamespace, types.UrlEncodedPath(p.PathWithNamespace))
+	return p, nil
+}
+
+func (c *CachedClient) GetGroup(gid string) (*types.Group, error) {
+	if obj, ok := c.groups.get(gid); ok {
+		return copyGroup(obj.(*types.Group)), nil
+	}
+
+	g, err := c.Interface.GetGroup(gid)
+	if nil != err {
+		return nil, err
+	}
+
+	c.groups.set(g.Id, copyGroup(g), gid, g.FullPath, types.UrlEncodedPath(g.FullPath))
+	return g, nil
+}
+
+// InvalidateProject removes cached project specified by id or path
+func (c *CachedClient) InvalidateProject(projId string) {
+	c.projects.invalidate(projId)
+}
+
+// InvalidateGroup removes cached group specified by id or path
+func (c *CachedClient) InvalidateGroup(gid string) {
+	c.groups.invalidate(gid)
+}
+
+// InvalidateAll removes all cached objects
+func (c *CachedClient) InvalidateAll() {
+	c.projects.clear()
+	c.groups.clear()
+}
+
+// invalidateProject removes cached project and all cached groups, since groups include their projects
+func (c *CachedClient) invalidateProject(projId string) {
+	c.InvalidateProject(projId)
+	c.groups.clear()
+}
+
+func (c *CachedClient) CreateProject(p *types.Project) (*types.Project, error) {
+	defer c.groups.clear()
+	return c.Interface.CreateProject(p)
+}
+
+func (c *CachedClient) UpdateProject(projId string, opts *types.UpdateProjectOpts) (*types.Project, error) {
+	defer c.invalidateProject(projId)
+	return c.Interface.UpdateProject(projId, opts)
+}
+
+func (c *CachedClient) DeleteProject(projId string) error {
+	defer c.invalidateProject(projId)
+	return c.Interface.DeleteProject(projId)
+}
+
+func (c *CachedClient) ArchiveProject(projId string) (*types.Project, error) {
+	defer c.invalidateProject(projId)
+	return c.Interface.ArchiveProject(projId)
+}
+
+func (c *CachedClient) UnarchiveProject(projId string) (*types.Project, error) {
+	defer c.invalidateProject(projId)
+	return c.Interface.UnarchiveProject(projId)
+}
+
+// ForkProject invalidates the forked project, of which forks count is changed
+func (c *CachedClient) ForkProject(projId, namespace string) (*types.Project, error) {
+	defer c.invalidateProject(projId)
+	return c.Interface.ForkProject(projId, namespace)
+}
+
+func (c *CachedClient) StarProject(projId string) error {
+	defer c.invalidateProject(projId)
+	return c.Interface.StarProject(projId)
+}
+
+func (c *CachedClient) UnstarProject(projId string) error {
+	defer c.invalidateProject(projId)
+	return c.Interface.UnstarProject(projId)
+}
+
+func (c *CachedClient) TransferProject(projId, namespace string) (*types.Project, error) {
+	defer c.invalidateProject(projId)
+	return c.Interface.TransferProject(projId, namespace)
+}
+
+func (c *CachedClient) TransferProjectToGroup(gid, projId string) error {
+	defer c.invalidateProject(projId)
+	return c.Interface.TransferProjectToGroup(gid, projId)
+}
+
+// UpdateGroup invalidates the group and all cached projects, since path of the group is part of paths of its projects
+func (c *CachedClient) UpdateGroup(gid string, g *types.Group) (*types.Group, error) {
+	defer c.projects.clear()
+	defer c.InvalidateGroup(gid)
+	return c.Interface.UpdateGroup(gid, g)
+}
+
+// DeleteGroup invalidates the group and all cached projects, since projects of the group are deleted as well
+func (c *CachedClient) DeleteGroup(gid string) error {
+	defer c.projects.clear()
+	defer c.InvalidateGroup(gid)
+	return c.Interface.DeleteGroup(gid)
+}
+
+// copyProject returns a deep copy of project so that cached objects are never shared with callers
+func copyProject(p *types.Project) *types.Project {
+	if nil == p {
+		return nil
+	}
+
+	np := *p
+	if nil != p.Owner {
+		o := *p.Owner
+		np.Owner = &o
+	}
+	if nil != p.Namespace {
+		ns := *p.Namespace
+		np.Namespace = &ns
+	}
+	np.ForkedFromProject = copyProject(p.ForkedFromProject)
+	return &np
+}
+
+// copyGroup returns a deep copy of group so that cached objects are never shared with callers
+func copyGroup(g *types.Group) *types.Group {
+	ng := *g
+	if nil != g.Projects {
+		ng.Projects = make([]*types.Project, len(g.Projects))
+		for i, p := range g.Projects {
+			ng.Projects[i] = copyProject(p)
+		}
+	}
+	return &ng
+}
+
+type cacheEntry struct {
+	obj      interface{}
+	expireAt time.Time
+	// All keys of the object, used to remove them along with the entry
+	keys []string
+}
+
+// ttlCache caches objects by numeric id. An object can be looked up by any of its keys, e.g. id or paths.
+// Expired objects are removed once read, and swept by set at most once per TTL.
+type ttlCache struct {
+	lock      sync.Mutex
+	ttl       time.Duration
+	entries   map[int]*cacheEntry
+	keys      map[string]int
+	nextSweep time.Time
+}
+
+func newTTLCache(ttl time.Duration) *ttlCache {
+	return &ttlCache{
+		ttl:     ttl,
+		entries: make(map[int]*cacheEntry),
+		keys:    make(map[string]int),
+	}
+}
+
+func (c *ttlCache) get(key string) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	id, ok := c.keys[key]
+	if !ok {
+		return nil, false
+	}
+	e := c.entries[id]
+	if time.Now().After(e.expireAt) {
+		c.remove(id)
+		return nil, false
+	}
+
+	return e.obj, true
+}
+
+// set caches obj of id, which can be looked up by id and all the given keys
+func (c *ttlCache) set(id int, obj interface{}, keys ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := time.Now()
+	if now.After(c.nextSweep) {
+		c.sweep(now)
+	}
+
+	c.remove(id)
+	e := &cacheEntry{
+		obj:      obj,
+		expireAt: now.Add(c.ttl),
+	}
+	for _, k := range append([]string{strconv.Itoa(id)}, keys...) {
+		if k == "" {
+			continue
+		}
+		if old, ok := c.keys[k]; ok && old != id {
+			// The key is moved to this object, e.g. path of a deleted project is reused
+			c.remove(old)
+		}
+		c.keys[k] = id
+		e.keys = append(e.keys, k)
+	}
+	c.entries[id] = e
+}
+
+// sweep removes all expired objects. Caller must hold the lock.
+func (c *ttlCache) sweep(now time.Time) {
+	for id, e := range c.entries {
+		if now.After(e.expireAt) {
+			c.remove(id)
+		}
+	}
+	c.nextSweep = now.Add(c.ttl)
+}
+
+// invalidate removes the object of key, which is either id or path
+func (c *ttlCache) invalidate(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	id, ok := c.keys[key]
+	if !ok {
+		var err error
+		if id, err = strconv.Atoi(key); nil != err {
+			return
+		}
+	}
+
+	c.remove(id)
+}
+
+// remove deletes the object of id and all its keys. Caller must hold the lock.
+func (c *ttlCache) remove(id int) {
+	e, ok := c.entries[id]
+	if !ok {
+		return
+	}
+
+	for _, k := range e.keys {
+		delete(c.keys, k)
+	}
+	delete(c.entries, id)
+}
+
+func (c *ttlCache) clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.entries = make(map[int]*cacheEntry)
+	c.keys = make(map[string]int)
+}
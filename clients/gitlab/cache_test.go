@@ -0,0 +1,176 @@
+package gitlab_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/haborhuang/go-tools/clients/gitlab"
+	"github.com/haborhuang/go-tools/clients/gitlab/gitlabtest"
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+// countingClient counts requests of getting projects and groups
+type countingClient struct {
+	gitlab.Interface
+	projects int
+	groups   int
+}
+
+func (c *countingClient) GetProject(projId string) (*types.Project, error) {
+	c.projects++
+	return c.Interface.GetProject(projId)
+}
+
+func (c *countingClient) GetGroup(gid string) (*types.Group, error) {
+	c.groups++
+	return c.Interface.GetGroup(gid)
+}
+
+func TestCachedClientHit(t *testing.T) {
+	s := gitlabtest.NewServer()
+	defer s.Close()
+	cc := &countingClient{Interface: s.NewClient()}
+	c := gitlab.NewCachedClient(cc, time.Minute)
+
+	p := s.AddProject(&types.Project{Name: "app"})
+	pid := strconv.Itoa(p.Id)
+	for _, key := range []string{pid, pid, p.PathWithNamespace, types.UrlEncodedPath(p.PathWithNamespace)} {
+		got, err := c.GetProject(key)
+		if nil != err {
+			t.Fatalf("Get project %s error: %v", key, err)
+		}
+		if got.Id != p.Id {
+			t.Fatalf("Expect project %d, got %d", p.Id, got.Id)
+		}
+	}
+	if cc.projects != 1 {
+		t.Fatalf("Expect 1 request, got %d", cc.projects)
+	}
+
+	// Changing the returned object must not change the cached one
+	got, _ := c.GetProject(pid)
+	got.Name = "changed"
+	got.Namespace.Path = "changed"
+	got, _ = c.GetProject(pid)
+	if got.Name != "app" || got.Namespace.Path == "changed" {
+		t.Fatalf("Cached project is changed by caller: %+v", got)
+	}
+}
+
+func TestCachedClientExpire(t *testing.T) {
+	s := gitlabtest.NewServer()
+	defer s.Close()
+	cc := &countingClient{Interface: s.NewClient()}
+	c := gitlab.NewCachedClient(cc, 10*time.Millisecond)
+
+	g := s.AddGroup(&types.Group{Name: "devops", Path: "devops"})
+	gid := strconv.Itoa(g.Id)
+	if _, err := c.GetGroup(gid); nil != err {
+		t.Fatalf("Get group error: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, err := c.GetGroup(gid); nil != err {
+		t.Fatalf("Get group error: %v", err)
+	}
+	if cc.groups != 2 {
+		t.Fatalf("Expect 2 requests, got %d", cc.groups)
+	}
+}
+
+func TestCachedClientInvalidate(t *testing.T) {
+	s := gitlabtest.NewServer()
+	defer s.Close()
+	cc := &countingClient{Interface: s.NewClient()}
+	c := gitlab.NewCachedClient(cc, time.Minute)
+
+	p := s.AddProject(&types.Project{Name: "app"})
+	pid := strconv.Itoa(p.Id)
+
+	// Cached by id and invalidated by path
+	c.GetProject(pid)
+	c.InvalidateProject(types.UrlEncodedPath(p.PathWithNamespace))
+	c.GetProject(pid)
+	if cc.projects != 2 {
+		t.Fatalf("Expect 2 requests, got %d", cc.projects)
+	}
+
+	// Cached by path and invalidated by changes via id
+	path := types.UrlEncodedPath(p.PathWithNamespace)
+	c.GetProject(path)
+	if _, err := c.UpdateProject(pid, &types.UpdateProjectOpts{Description: types.String("app")}); nil != err {
+		t.Fatalf("Update project error: %v", err)
+	}
+	got, err := c.GetProject(path)
+	if nil != err {
+		t.Fatalf("Get project error: %v", err)
+	}
+	if got.Description != "app" {
+		t.Fatalf("Expect updated description, got %q", got.Description)
+	}
+
+	// Transferring project invalidates groups
+	g := s.AddGroup(&types.Group{Name: "devops", Path: "devops"})
+	gid := strconv.Itoa(g.Id)
+	c.GetGroup(gid)
+	if err := c.TransferProjectToGroup(gid, pid); nil != err {
+		t.Fatalf("Transfer project error: %v", err)
+	}
+	if got, err = c.GetProject(pid); nil != err {
+		t.Fatalf("Get project error: %v", err)
+	}
+	if got.Namespace == nil || got.Namespace.Id != g.Id {
+		t.Fatalf("Expect project in group %d, got %+v", g.Id, got.Namespace)
+	}
+	c.GetGroup(gid)
+	if cc.groups != 2 {
+		t.Fatalf("Expect 2 group requests, got %d", cc.groups)
+	}
+
+	// Deleting group invalidates its projects
+	if err := c.DeleteGroup(gid); nil != err {
+		t.Fatalf("Delete group error: %v", err)
+	}
+	if _, err := c.GetProject(pid); nil == err {
+		t.Fatalf("Expect error of getting project of deleted group")
+	}
+}
+
+func TestCachedClientProjectChanges(t *testing.T) {
+	s := gitlabtest.NewServer()
+	defer s.Close()
+	cc := &countingClient{Interface: s.NewClient()}
+	c := gitlab.NewCachedClient(cc, time.Minute)
+
+	g := s.AddGroup(&types.Group{Name: "devops", Path: "devops"})
+	gid := strconv.Itoa(g.Id)
+	p := s.AddProject(&types.Project{Name: "app", NamespaceId: g.Id})
+	pid := strconv.Itoa(p.Id)
+
+	// Each change of project invalidates the cached group including it, which is requested again
+	changes := []func() error{
+		func() error {
+			_, err := c.CreateProject(&types.Project{Name: "web", NamespaceId: g.Id})
+			return err
+		},
+		func() error {
+			_, err := c.ForkProject(pid, "")
+			return err
+		},
+		func() error {
+			_, err := c.ArchiveProject(pid)
+			return err
+		},
+		func() error { return c.DeleteProject(pid) },
+	}
+	for i, change := range changes {
+		c.GetGroup(gid)
+		if err := change(); nil != err {
+			t.Fatalf("Change %d error: %v", i, err)
+		}
+		c.GetGroup(gid)
+		if cc.groups != i+2 {
+			t.Fatalf("Expect group invalidated by change %d", i)
+		}
+	}
+}
@@ -9,17 +9,23 @@ import (
 	"net/http"
 	"encoding/json"
 	"io/ioutil"
+	"mime"
 )
 
 type Config struct {
 	Url    string
 	APIVer string
+	// Token of the AuthMode
 	Token string
+	// One of AuthMode* constants. Defaults to AuthModePrivateToken.
+	AuthMode string
+	// Only used by AuthModeOAuth2 to refresh the token
+	OAuth2 *OAuth2Config
 }
 
 type Client struct {
 	url *url.URL
-	token string
+	auth authenticator
 }
 
 func NewClientOrDie(conf Config) *Client {
@@ -41,26 +47,34 @@ func NewClient(conf Config) (*Client, error) {
 		conf.APIVer = "v4"
 	}
 
+	auth, err := newAuthenticator(*u, conf)
+	if nil != err {
+		return nil, err
+	}
+
 	u.Path = "/api/" + conf.APIVer
 
 	return &Client{
 		url: u,
-		token: conf.Token,
+		auth: auth,
 	}, nil
 }
 
 func (c *Client) newRequest() *clienttool.HttpRequest {
-	return clienttool.NewHttpReq(*c.url).SetHeader("PRIVATE-TOKEN", c.token)
+	return c.auth.authenticate(clienttool.NewHttpReq(*c.url))
 }
 
 type response struct {
 	req *clienttool.HttpRequest
 	respHeader http.Header
+	// Notified of 401 responses if not nil
+	auth authenticator
 }
 
 func (c *Client) newResponse(r *clienttool.HttpRequest) *response {
 	return &response{
 		req: r,
+		auth: c.auth,
 	}
 }
 
@@ -70,6 +84,10 @@ func (r *response) doRaw() (*http.Response, error) {
 		return nil, err
 	}
 
+	if resp.StatusCode == http.StatusUnauthorized && nil != r.auth && nil != resp.Request {
+		r.auth.unauthorized(resp.Request)
+	}
+
 	if err := types.ParseErr(resp); nil != err {
 		return nil, err
 	}
@@ -112,4 +130,14 @@ func (r *response) intoJson(expected interface{}) error {
 func (r *response) extractRespHeaders(header http.Header) *response {
 	r.respHeader = header
 	return r
-}
\ No newline at end of file
+}
+
+// attachmentName extracts file name from Content-Disposition header of response
+func attachmentName(resp *http.Response) (string, error) {
+	_, params, err := mime.ParseMediaType(resp.Header.Get("content-disposition"))
+	if nil != err {
+		return "", err
+	}
+
+	return params["filename"], nil
+}
@@ -11,7 +11,19 @@ func commitsPath(projId string) string {
 	return fmt.Sprintf(commitsPathFmt, projId)
 }
 
-const commitsPathFmt = projectPathFmt + "/repository/commits"
+func commitStatusesPath(projId, sha string) string {
+	return fmt.Sprintf(commitStatusesPathFmt, projId, sha)
+}
+
+func setCommitStatusPath(projId, sha string) string {
+	return fmt.Sprintf(setCommitStatusPathFmt, projId, sha)
+}
+
+const (
+	commitsPathFmt = projectPathFmt + "/repository/commits"
+	commitStatusesPathFmt = commitsPathFmt + "/%s/statuses"
+	setCommitStatusPathFmt = projectPathFmt + "/statuses/%s"
+)
 
 func (c *Client) CreateCommit(pid string, payload *types.CommitPayload) (*types.Commit, error) {
 	var commit *types.Commit
@@ -38,4 +50,32 @@ func (c *Client) ListCommits(pid, ref string, paging *types.Pagination) ([]*type
 	).intoJson(&commits)
 
 	return commits, err
-}
\ No newline at end of file
+}
+
+// SetCommitStatus adds or updates the status with the same name of commit
+func (c *Client) SetCommitStatus(pid, sha string, opts *types.SetCommitStatusOpts) (*types.CommitStatus, error) {
+	if err := opts.Check(); nil != err {
+		return nil, fmt.Errorf("Check commit status parameters error: %v", err)
+	}
+
+	var status *types.CommitStatus
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPost).RawSubPath(setCommitStatusPath(pid, sha)).JsonBody(opts),
+	).intoJson(&status)
+
+	return status, err
+}
+
+func (c *Client) ListCommitStatuses(pid, sha string, opts *types.ListCommitStatusesOpts) ([]*types.CommitStatus, error) {
+	q, err := opts.ToQuery()
+	if nil != err {
+		return nil, fmt.Errorf("Check list commit statuses parameters error: %v", err)
+	}
+
+	var statuses []*types.CommitStatus
+	err = c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(commitStatusesPath(pid, sha)).Query(q),
+	).intoJson(&statuses)
+
+	return statuses, err
+}
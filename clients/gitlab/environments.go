@@ -0,0 +1,161 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+const (
+	environmentsPathFmt = projectPathFmt + "/environments"
+	environmentPathFmt  = environmentsPathFmt + "/%d"
+	deploymentsPathFmt  = projectPathFmt + "/deployments"
+	deploymentPathFmt   = deploymentsPathFmt + "/%d"
+)
+
+func environmentsPath(projId string) string {
+	return fmt.Sprintf(environmentsPathFmt, projId)
+}
+
+func environmentPath(projId string, envId int) string {
+	return fmt.Sprintf(environmentPathFmt, projId, envId)
+}
+
+func deploymentsPath(projId string) string {
+	return fmt.Sprintf(deploymentsPathFmt, projId)
+}
+
+func deploymentPath(projId string, deployId int) string {
+	return fmt.Sprintf(deploymentPathFmt, projId, deployId)
+}
+
+// ListEnvironments lists environments of project, filtered by name or state if not empty
+func (c *Client) ListEnvironments(pid, name, state string, paging *types.Pagination) ([]*types.Environment, int, error) {
+	q := make(url.Values)
+	if name != "" {
+		q.Set("name", name)
+	}
+	if state != "" {
+		q.Set("states", state)
+	}
+	if nil != paging {
+		paging.ToQuery(q)
+	}
+
+	var res []*types.Environment
+	respHeader := make(http.Header)
+	respHeader.Set(types.RespHeaderTotalPages, "")
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(environmentsPath(pid)).Query(q),
+	).extractRespHeaders(respHeader).intoJson(&res)
+
+	pages, _ := strconv.Atoi(respHeader.Get(types.RespHeaderTotalPages))
+	return res, pages, err
+}
+
+func (c *Client) GetEnvironment(pid string, envId int) (*types.Environment, error) {
+	var env *types.Environment
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(environmentPath(pid, envId)),
+	).intoJson(&env)
+
+	return env, err
+}
+
+func (c *Client) CreateEnvironment(pid string, env *types.Environment) (*types.Environment, error) {
+	var ne *types.Environment
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPost).RawSubPath(environmentsPath(pid)).JsonBody(env),
+	).intoJson(&ne)
+
+	return ne, err
+}
+
+func (c *Client) UpdateEnvironment(pid string, envId int, env *types.Environment) (*types.Environment, error) {
+	var ne *types.Environment
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPut).RawSubPath(environmentPath(pid, envId)).JsonBody(env),
+	).intoJson(&ne)
+
+	return ne, err
+}
+
+// DeleteEnvironment deletes the environment, which should be stopped at first
+func (c *Client) DeleteEnvironment(pid string, envId int) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodDelete).RawSubPath(environmentPath(pid, envId)),
+	).do()
+}
+
+func (c *Client) StopEnvironment(pid string, envId int) (*types.Environment, error) {
+	var env *types.Environment
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPost).RawSubPath(environmentPath(pid, envId) + "/stop"),
+	).intoJson(&env)
+
+	return env, err
+}
+
+func (c *Client) ListDeployments(pid string, opts *types.ListDeploymentsOpts) ([]*types.Deployment, int, error) {
+	q, err := opts.ToQuery()
+	if nil != err {
+		return nil, 0, fmt.Errorf("Check list deployments parameters error: %v", err)
+	}
+
+	var res []*types.Deployment
+	respHeader := make(http.Header)
+	respHeader.Set(types.RespHeaderTotalPages, "")
+	err = c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(deploymentsPath(pid)).Query(q),
+	).extractRespHeaders(respHeader).intoJson(&res)
+
+	pages, _ := strconv.Atoi(respHeader.Get(types.RespHeaderTotalPages))
+	return res, pages, err
+}
+
+func (c *Client) GetDeployment(pid string, deployId int) (*types.Deployment, error) {
+	var d *types.Deployment
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(deploymentPath(pid, deployId)),
+	).intoJson(&d)
+
+	return d, err
+}
+
+// CreateDeployment records a deployment made outside of GitLab pipelines
+func (c *Client) CreateDeployment(pid string, opts *types.CreateDeploymentOpts) (*types.Deployment, error) {
+	if nil == opts || opts.Environment == "" || opts.SHA == "" || opts.Ref == "" {
+		return nil, fmt.Errorf("Missing environment, sha or ref of deployment")
+	}
+	if !types.ValidDeploymentStatus(opts.Status) {
+		return nil, fmt.Errorf("Invalid status '%s'", opts.Status)
+	}
+
+	var d *types.Deployment
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPost).RawSubPath(deploymentsPath(pid)).JsonBody(opts),
+	).intoJson(&d)
+
+	return d, err
+}
+
+type deploymentStatusReq struct {
+	Status string `json:"status"`
+}
+
+func (c *Client) UpdateDeploymentStatus(pid string, deployId int, status string) (*types.Deployment, error) {
+	if !types.ValidDeploymentStatus(status) {
+		return nil, fmt.Errorf("Invalid status '%s'", status)
+	}
+
+	var d *types.Deployment
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPut).RawSubPath(deploymentPath(pid, deployId)).
+			JsonBody(deploymentStatusReq{Status: status}),
+	).intoJson(&d)
+
+	return d, err
+}
@@ -0,0 +1,53 @@
+package gitlab_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haborhuang/go-tools/clients/gitlab"
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+func TestListDeployments(t *testing.T) {
+	var query string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/root%2Fapp/deployments" {
+			t.Errorf("Unexpected path '%s'", r.URL.EscapedPath())
+		}
+		query = r.URL.RawQuery
+		w.Header().Set(types.RespHeaderTotalPages, "3")
+		json.NewEncoder(w).Encode([]*types.Deployment{{Id: 2}, {Id: 1}})
+	}))
+	defer s.Close()
+	c := gitlab.NewClientOrDie(gitlab.Config{Url: s.URL})
+
+	cases := []struct {
+		opts  *types.ListDeploymentsOpts
+		query string
+	}{
+		{nil, ""},
+		{&types.ListDeploymentsOpts{Sort: "asc"}, "sort=asc"},
+		{
+			&types.ListDeploymentsOpts{Environment: "prod", Status: types.DeploymentStatusSuccess, OrderBy: "id", Sort: "desc"},
+			"environment=prod&order_by=id&sort=desc&status=success",
+		},
+	}
+	for _, tc := range cases {
+		res, pages, err := c.ListDeployments(types.UrlEncodedPath("root/app"), tc.opts)
+		if nil != err {
+			t.Fatalf("List deployments error: %v", err)
+		}
+		if query != tc.query {
+			t.Errorf("Expect query '%s', got '%s'", tc.query, query)
+		}
+		if len(res) != 2 || res[0].Id != 2 || pages != 3 {
+			t.Errorf("Unexpected deployments %+v of %d pages", res, pages)
+		}
+	}
+
+	if _, _, err := c.ListDeployments("1", &types.ListDeploymentsOpts{Sort: "up"}); nil == err {
+		t.Errorf("Expect error of invalid sort")
+	}
+}
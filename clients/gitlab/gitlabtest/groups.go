@@ -0,0 +1,206 @@
+package gitlabtest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+// AddGroup adds a group directly, as if created by API. Nil is returned if the group is invalid.
+func (s *Server) AddGroup(g *types.Group) *types.Group {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	ng, _ := s.addGroup(g)
+	if nil == ng {
+		return nil
+	}
+	return copyGroup(ng)
+}
+
+func (s *Server) addGroup(g *types.Group) (*types.Group, string) {
+	if g.Path == "" {
+		g.Path = g.Name
+	}
+	if g.Name == "" {
+		g.Name = g.Path
+	}
+	if g.Path == "" {
+		return nil, "path is missing"
+	}
+
+	ng := *g
+	ng.Id = s.nextId()
+	ng.FullPath = ng.Path
+	ng.FullName = ng.Name
+	if ng.ParentId != 0 {
+		parent, ok := s.groups[ng.ParentId]
+		if !ok {
+			return nil, "parent not found"
+		}
+		ng.FullPath = parent.FullPath + "/" + ng.Path
+		ng.FullName = parent.FullName + " / " + ng.Name
+	}
+	if nil != s.findGroup(ng.FullPath) {
+		return nil, "Failed to save group {:path=>[\"has already been taken\"]}"
+	}
+	if ng.Visibility == "" {
+		ng.Visibility = types.VisibilityPrivate
+	}
+	ng.WebURL = s.URL + "/groups/" + ng.FullPath
+	ng.Projects = nil
+
+	s.groups[ng.Id] = &ng
+	return &ng, ""
+}
+
+func copyGroup(g *types.Group) *types.Group {
+	ng := *g
+	return &ng
+}
+
+// findGroup finds group by id or full path
+func (s *Server) findGroup(gid string) *types.Group {
+	if id, err := strconv.Atoi(gid); nil == err {
+		return s.groups[id]
+	}
+
+	for _, g := range s.groups {
+		if g.FullPath == gid {
+			return g
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) groupNamespace(g *types.Group) *types.Namespace {
+	return &types.Namespace{
+		Id:       g.Id,
+		Name:     g.Name,
+		Path:     g.Path,
+		Kind:     types.NamespaceKindGroup,
+		FullPath: g.FullPath,
+		ParentId: g.ParentId,
+	}
+}
+
+func (s *Server) sortedGroups(filter func(*types.Group) bool) []*types.Group {
+	res := make([]*types.Group, 0)
+	for _, g := range s.groups {
+		if filter(g) {
+			res = append(res, copyGroup(g))
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Id < res[j].Id
+	})
+
+	return res
+}
+
+func (s *Server) serveGroups(w http.ResponseWriter, r *request) {
+	switch {
+	case r.match(http.MethodGet, "groups"):
+		search := r.URL.Query().Get("search")
+		s.writeGroups(w, r, s.sortedGroups(func(g *types.Group) bool {
+			return search == "" || strings.Contains(g.Name, search) || strings.Contains(g.Path, search)
+		}))
+	case r.match(http.MethodPost, "groups"):
+		var g types.Group
+		if err := decodeBody(r, &g); nil != err {
+			writeErr(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ng, msg := s.addGroup(&g)
+		if nil == ng {
+			writeErr(w, http.StatusBadRequest, msg)
+			return
+		}
+		writeJson(w, http.StatusCreated, ng)
+	default:
+		if len(r.segs) < 2 {
+			writeErr(w, http.StatusNotFound, "404 Not Found")
+			return
+		}
+
+		g := s.findGroup(r.segs[1])
+		if nil == g {
+			writeNotFound(w, "Group")
+			return
+		}
+
+		s.serveGroup(w, r, g)
+	}
+}
+
+func (s *Server) serveGroup(w http.ResponseWriter, r *request, g *types.Group) {
+	switch {
+	case r.match(http.MethodGet, "groups", "*"):
+		ng := copyGroup(g)
+		for _, p := range s.sortedProjects(func(p *project) bool { return p.Namespace.Id == g.Id }) {
+			ng.Projects = append(ng.Projects, p)
+		}
+		writeJson(w, http.StatusOK, ng)
+	case r.match(http.MethodPut, "groups", "*"):
+		var ng types.Group
+		if err := decodeBody(r, &ng); nil != err {
+			writeErr(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if ng.Name != "" {
+			g.Name = ng.Name
+		}
+		if ng.Description != "" {
+			g.Description = ng.Description
+		}
+		if ng.Visibility != "" {
+			g.Visibility = ng.Visibility
+		}
+		writeJson(w, http.StatusOK, g)
+	case r.match(http.MethodDelete, "groups", "*"):
+		s.deleteGroup(g)
+		writeJson(w, http.StatusAccepted, map[string]string{"message": "202 Accepted"})
+	case r.match(http.MethodGet, "groups", "*", "subgroups"):
+		s.writeGroups(w, r, s.sortedGroups(func(sg *types.Group) bool { return sg.ParentId == g.Id }))
+	case r.match(http.MethodGet, "groups", "*", "projects"):
+		projs := s.sortedProjects(func(p *project) bool { return p.Namespace.Id == g.Id })
+		start, end := paginate(w, r, len(projs))
+		writeJson(w, http.StatusOK, projs[start:end])
+	case r.match(http.MethodPost, "groups", "*", "projects", "*"):
+		p := s.findProject(r.segs[3])
+		if nil == p {
+			writeNotFound(w, "Project")
+			return
+		}
+
+		s.moveProject(p, s.groupNamespace(g))
+		writeJson(w, http.StatusCreated, g)
+	default:
+		writeErr(w, http.StatusNotFound, "404 Not Found")
+	}
+}
+
+func (s *Server) writeGroups(w http.ResponseWriter, r *request, groups []*types.Group) {
+	start, end := paginate(w, r, len(groups))
+	writeJson(w, http.StatusOK, groups[start:end])
+}
+
+func (s *Server) deleteGroup(g *types.Group) {
+	for _, sg := range s.groups {
+		if sg.ParentId == g.Id {
+			s.deleteGroup(sg)
+		}
+	}
+	for id, p := range s.projects {
+		if p.Namespace.Id == g.Id {
+			delete(s.projects, id)
+		}
+	}
+	delete(s.groups, g.Id)
+}
@@ -0,0 +1,128 @@
+package gitlabtest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+// SetPipelineStatus changes status of pipeline, e.g. to simulate that a pipeline finished.
+// The project is specified by id or unescaped path with namespace.
+func (s *Server) SetPipelineStatus(projId string, pipelineId int, status string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	p := s.findProject(projId)
+	if nil == p {
+		return fmt.Errorf("Project '%s' not found", projId)
+	}
+
+	pl := p.pipeline(pipelineId)
+	if nil == pl {
+		return fmt.Errorf("Pipeline %d not found", pipelineId)
+	}
+
+	now := time.Now().UTC()
+	pl.Status = status
+	pl.UpdatedAt = &now
+	if pl.StartedAt == nil && status != "pending" {
+		pl.StartedAt = &now
+	}
+	if pl.Finished() {
+		pl.FinishedAt = &now
+		duration := int64(now.Sub(*pl.StartedAt).Seconds())
+		pl.Duration = &duration
+	}
+
+	return nil
+}
+
+func (p *project) pipeline(id int) *types.Pipeline {
+	for _, pl := range p.pipelines {
+		if pl.Id == id {
+			return pl
+		}
+	}
+
+	return nil
+}
+
+type createPipelineReq struct {
+	Ref string `json:"ref"`
+}
+
+func (s *Server) servePipelines(w http.ResponseWriter, r *request, p *project) {
+	switch {
+	case r.match(http.MethodPost, "projects", "*", "pipeline"):
+		ref := r.URL.Query().Get("ref")
+		if ref == "" {
+			var req createPipelineReq
+			if err := decodeBody(r, &req); nil != err {
+				writeErr(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			ref = req.Ref
+		}
+
+		b, ok := p.branches[ref]
+		if !ok {
+			b, ok = p.tags[ref]
+		}
+		if !ok {
+			writeErr(w, http.StatusBadRequest, "Reference not found")
+			return
+		}
+
+		now := time.Now().UTC()
+		pl := &types.Pipeline{
+			PipelineBrief: types.PipelineBrief{
+				Id:     s.nextId(),
+				SHA:    b.head().Id,
+				Ref:    ref,
+				Status: "pending",
+			},
+			User:      *s.user,
+			CreatedAt: &now,
+			UpdatedAt: &now,
+		}
+		if len(b.commits) > 1 {
+			pl.BeforeSHA = b.commits[1].Id
+		}
+		p.pipelines = append([]*types.Pipeline{pl}, p.pipelines...)
+		writeJson(w, http.StatusCreated, pl)
+	case r.match(http.MethodGet, "projects", "*", "pipelines"):
+		q := r.URL.Query()
+		res := make([]*types.PipelineBrief, 0)
+		for _, pl := range p.pipelines {
+			if ref := q.Get("ref"); ref != "" && pl.Ref != ref {
+				continue
+			}
+			if status := q.Get("status"); status != "" && pl.Status != status {
+				continue
+			}
+			brief := pl.PipelineBrief
+			res = append(res, &brief)
+		}
+		if q.Get("sort") == "asc" {
+			for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+				res[i], res[j] = res[j], res[i]
+			}
+		}
+
+		start, end := paginate(w, r, len(res))
+		writeJson(w, http.StatusOK, res[start:end])
+	case r.match(http.MethodGet, "projects", "*", "pipelines", "*"):
+		id, _ := strconv.Atoi(r.segs[3])
+		pl := p.pipeline(id)
+		if nil == pl {
+			writeNotFound(w, "Pipeline")
+			return
+		}
+		writeJson(w, http.StatusOK, pl)
+	default:
+		writeErr(w, http.StatusNotFound, "404 Not Found")
+	}
+}
@@ -0,0 +1,361 @@
+package gitlabtest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+type project struct {
+	types.Project
+	branches map[string]*branch
+	// Snapshots of repository by commit id and tag
+	commits   map[string]*branch
+	tags      map[string]*branch
+	starred   bool
+	hooks     []*types.Hook
+	pipelines []*types.Pipeline
+}
+
+// AddProject adds a project directly, as if created by API
+func (s *Server) AddProject(p *types.Project) *types.Project {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	np, _ := s.addProject(p)
+	if nil == np {
+		return nil
+	}
+	return np.copy()
+}
+
+func (s *Server) addProject(p *types.Project) (*project, string) {
+	if p.Path == "" {
+		p.Path = strings.ToLower(strings.Replace(p.Name, " ", "-", -1))
+	}
+	if p.Name == "" {
+		p.Name = p.Path
+	}
+	if p.Path == "" {
+		return nil, "name is missing"
+	}
+
+	ns := s.userNs
+	if p.NamespaceId != 0 {
+		g, ok := s.groups[p.NamespaceId]
+		if !ok {
+			return nil, "Namespace is not valid"
+		}
+		ns = s.groupNamespace(g)
+	}
+	if nil != s.findProject(ns.FullPath+"/"+p.Path) {
+		return nil, "Failed to save project {:path=>[\"has already been taken\"]}"
+	}
+
+	np := &project{
+		Project:  *p,
+		branches: make(map[string]*branch),
+		commits:  make(map[string]*branch),
+		tags:     make(map[string]*branch),
+	}
+	np.Id = s.nextId()
+	np.NamespaceId = 0
+	np.CreatedAtRaw = time.Now().UTC().Format(time.RFC3339)
+	if np.Visibility == "" {
+		np.Visibility = types.VisibilityPrivate
+	}
+	s.moveProject(np, ns)
+
+	s.projects[np.Id] = np
+	return np, ""
+}
+
+func (s *Server) moveProject(p *project, ns *types.Namespace) {
+	p.Namespace = ns
+	p.PathWithNamespace = ns.FullPath + "/" + p.Path
+	p.WebUrl = s.URL + "/" + p.PathWithNamespace
+	p.HttpRepoUrl = p.WebUrl + ".git"
+	p.SshRepoUrl = "git@" + strings.TrimPrefix(s.URL, "http://") + ":" + p.PathWithNamespace + ".git"
+}
+
+// findNamespace finds namespace of the current user or group by id or full path.
+// The namespace of the current user is returned if ns is empty.
+func (s *Server) findNamespace(ns string) *types.Namespace {
+	if ns == "" || ns == s.userNs.FullPath || ns == strconv.Itoa(s.userNs.Id) {
+		return s.userNs
+	}
+
+	g := s.findGroup(ns)
+	if nil == g {
+		return nil
+	}
+	return s.groupNamespace(g)
+}
+
+func (p *project) copy() *types.Project {
+	np := p.Project
+	return &np
+}
+
+// findProject finds project by id or path with namespace
+func (s *Server) findProject(pid string) *project {
+	if id, err := strconv.Atoi(pid); nil == err {
+		return s.projects[id]
+	}
+
+	for _, p := range s.projects {
+		if p.PathWithNamespace == pid {
+			return p
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) sortedProjects(filter func(*project) bool) []*types.Project {
+	res := make([]*types.Project, 0)
+	for _, p := range s.projects {
+		if filter(p) {
+			res = append(res, p.copy())
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Id < res[j].Id
+	})
+
+	return res
+}
+
+func (s *Server) serveProjects(w http.ResponseWriter, r *request) {
+	switch {
+	case r.match(http.MethodGet, "projects"):
+		q := r.URL.Query()
+		search := q.Get("search")
+		archived := q.Get("archived")
+		projs := s.sortedProjects(func(p *project) bool {
+			if search != "" && !strings.Contains(p.Name, search) && !strings.Contains(p.Path, search) {
+				return false
+			}
+			return archived == "" || archived == strconv.FormatBool(p.Archived)
+		})
+		start, end := paginate(w, r, len(projs))
+		writeJson(w, http.StatusOK, projs[start:end])
+	case r.match(http.MethodPost, "projects"):
+		var p types.Project
+		if err := decodeBody(r, &p); nil != err {
+			writeErr(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		np, msg := s.addProject(&p)
+		if nil == np {
+			writeErr(w, http.StatusBadRequest, msg)
+			return
+		}
+		writeJson(w, http.StatusCreated, np.copy())
+	default:
+		if len(r.segs) < 2 {
+			writeErr(w, http.StatusNotFound, "404 Not Found")
+			return
+		}
+
+		p := s.findProject(r.segs[1])
+		if nil == p {
+			writeNotFound(w, "Project")
+			return
+		}
+
+		switch {
+		case len(r.segs) > 2 && r.segs[2] == "repository":
+			s.serveRepository(w, r, p)
+		case len(r.segs) > 2 && (r.segs[2] == "pipeline" || r.segs[2] == "pipelines"):
+			s.servePipelines(w, r, p)
+		case len(r.segs) > 2 && r.segs[2] == "hooks":
+			s.serveHooks(w, r, p)
+		default:
+			s.serveProject(w, r, p)
+		}
+	}
+}
+
+func (s *Server) serveProject(w http.ResponseWriter, r *request, p *project) {
+	switch {
+	case r.match(http.MethodGet, "projects", "*"):
+		writeJson(w, http.StatusOK, p.copy())
+	case r.match(http.MethodPut, "projects", "*"):
+		var opts types.UpdateProjectOpts
+		if err := decodeBody(r, &opts); nil != err {
+			writeErr(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if nil != opts.Name {
+			p.Name = *opts.Name
+		}
+		if nil != opts.Description {
+			p.Description = *opts.Description
+		}
+		if nil != opts.DefaultBranch {
+			p.DefaultBranch = *opts.DefaultBranch
+		}
+		if nil != opts.Visibility {
+			p.Visibility = *opts.Visibility
+		}
+		if nil != opts.IssuesEnabled {
+			p.IssuesEnabled = *opts.IssuesEnabled
+		}
+		if nil != opts.MergeRequestsEnabled {
+			p.MergeRequestsEnabled = *opts.MergeRequestsEnabled
+		}
+		if nil != opts.WikiEnabled {
+			p.WikiEnabled = *opts.WikiEnabled
+		}
+		if nil != opts.SharedRunners {
+			p.SharedRunners = *opts.SharedRunners
+		}
+		writeJson(w, http.StatusOK, p.copy())
+	case r.match(http.MethodDelete, "projects", "*"):
+		delete(s.projects, p.Id)
+		writeJson(w, http.StatusAccepted, map[string]string{"message": "202 Accepted"})
+	case r.match(http.MethodPost, "projects", "*", "archive"):
+		p.Archived = true
+		writeJson(w, http.StatusCreated, p.copy())
+	case r.match(http.MethodPost, "projects", "*", "unarchive"):
+		p.Archived = false
+		writeJson(w, http.StatusCreated, p.copy())
+	case r.match(http.MethodPost, "projects", "*", "star"):
+		if p.starred {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		p.starred = true
+		p.StarCount++
+		writeJson(w, http.StatusCreated, p.copy())
+	case r.match(http.MethodPost, "projects", "*", "unstar"):
+		if !p.starred {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		p.starred = false
+		p.StarCount--
+		writeJson(w, http.StatusCreated, p.copy())
+	case r.match(http.MethodPost, "projects", "*", "fork"):
+		s.forkProject(w, r, p)
+	case r.match(http.MethodPut, "projects", "*", "transfer"):
+		var req struct {
+			Namespace string `json:"namespace"`
+		}
+		if err := decodeBody(r, &req); nil != err {
+			writeErr(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ns := s.findNamespace(req.Namespace)
+		if nil == ns {
+			writeNotFound(w, "Namespace")
+			return
+		}
+		if nil != s.findProject(ns.FullPath+"/"+p.Path) {
+			writeErr(w, http.StatusBadRequest, "Project with same path in target namespace already exists")
+			return
+		}
+		s.moveProject(p, ns)
+		writeJson(w, http.StatusOK, p.copy())
+	default:
+		writeErr(w, http.StatusNotFound, "404 Not Found")
+	}
+}
+
+func (s *Server) serveHooks(w http.ResponseWriter, r *request, p *project) {
+	switch {
+	case r.match(http.MethodGet, "projects", "*", "hooks"):
+		writeJson(w, http.StatusOK, p.hooks)
+	case r.match(http.MethodPost, "projects", "*", "hooks"):
+		var h types.Hook
+		if err := decodeBody(r, &h); nil != err {
+			writeErr(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if h.Url == "" {
+			writeErr(w, http.StatusBadRequest, "url is missing")
+			return
+		}
+
+		h.Id = s.nextId()
+		h.CreatedAtRaw = time.Now().UTC().Format(time.RFC3339)
+		p.hooks = append(p.hooks, &h)
+		writeJson(w, http.StatusCreated, &h)
+	case r.match(http.MethodPut, "projects", "*", "hooks", "*"):
+		hid, _ := strconv.Atoi(r.segs[3])
+		for _, h := range p.hooks {
+			if h.Id != hid {
+				continue
+			}
+
+			var nh types.Hook
+			if err := decodeBody(r, &nh); nil != err {
+				writeErr(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if nh.Url != "" {
+				h.Url = nh.Url
+			}
+			h.HookFlags = nh.HookFlags
+			writeJson(w, http.StatusOK, h)
+			return
+		}
+		writeNotFound(w, "Hook")
+	default:
+		writeErr(w, http.StatusNotFound, "404 Not Found")
+	}
+}
+
+func (s *Server) forkProject(w http.ResponseWriter, r *request, p *project) {
+	var req struct {
+		Namespace string `json:"namespace"`
+	}
+	if err := decodeBody(r, &req); nil != err {
+		writeErr(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ns := s.findNamespace(req.Namespace)
+	if nil == ns {
+		writeNotFound(w, "Namespace")
+		return
+	}
+	if nil != s.findProject(ns.FullPath+"/"+p.Path) {
+		writeErr(w, http.StatusConflict, "Project namespace name has already been taken")
+		return
+	}
+
+	// Branches and snapshots are never changed in place, so they are shared with the fork
+	fork := &project{
+		Project:  p.Project,
+		branches: make(map[string]*branch, len(p.branches)),
+		commits:  make(map[string]*branch, len(p.commits)),
+		tags:     make(map[string]*branch, len(p.tags)),
+	}
+	for k, v := range p.branches {
+		fork.branches[k] = v
+	}
+	for k, v := range p.commits {
+		fork.commits[k] = v
+	}
+	for k, v := range p.tags {
+		fork.tags[k] = v
+	}
+	fork.Id = s.nextId()
+	fork.CreatedAtRaw = time.Now().UTC().Format(time.RFC3339)
+	fork.StarCount, fork.ForksCount, fork.Archived = 0, 0, false
+	fork.ForkedFromProject = p.copy()
+	s.moveProject(fork, ns)
+	s.projects[fork.Id] = fork
+
+	p.ForksCount++
+	writeJson(w, http.StatusCreated, fork.copy())
+}
@@ -0,0 +1,398 @@
+package gitlabtest
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+type branch struct {
+	// File contents by path
+	files map[string]string
+	// Commits, the newest at first
+	commits []*types.Commit
+}
+
+func (b *branch) head() *types.Commit {
+	if len(b.commits) == 0 {
+		return nil
+	}
+	return b.commits[0]
+}
+
+func (b *branch) clone() *branch {
+	nb := &branch{
+		files:   make(map[string]string, len(b.files)),
+		commits: append([]*types.Commit(nil), b.commits...),
+	}
+	for k, v := range b.files {
+		nb.files[k] = v
+	}
+
+	return nb
+}
+
+// ref returns the snapshot of repository at branch, tag or commit, or the default branch if name is empty.
+// Commit can be specified by its full id or a prefix of at least 7 characters.
+func (p *project) ref(name string) *branch {
+	if name == "" {
+		name = p.DefaultBranch
+	}
+	if b, ok := p.branches[name]; ok {
+		return b
+	}
+	if b, ok := p.tags[name]; ok {
+		return b
+	}
+	if b, ok := p.commits[name]; ok {
+		return b
+	}
+
+	if len(name) >= 7 {
+		for id, b := range p.commits {
+			if strings.HasPrefix(id, name) {
+				return b
+			}
+		}
+	}
+	return nil
+}
+
+// AddTag creates tag of the project specified by id or path at ref, and returns the tagged commit.
+// Nil is returned if the project or ref is not found.
+func (s *Server) AddTag(projId, tag, ref string) *types.Commit {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	p := s.findProject(projId)
+	if nil == p {
+		return nil
+	}
+	b := p.ref(ref)
+	if nil == b || nil == b.head() {
+		return nil
+	}
+
+	p.tags[tag] = b
+	commit := *b.head()
+	return &commit
+}
+
+func (s *Server) serveRepository(w http.ResponseWriter, r *request, p *project) {
+	switch {
+	case r.match(http.MethodPost, "projects", "*", "repository", "commits"):
+		var payload types.CommitPayload
+		if err := decodeBody(r, &payload); nil != err {
+			writeErr(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		commit, msg := s.commit(p, &payload)
+		if nil == commit {
+			writeErr(w, http.StatusBadRequest, msg)
+			return
+		}
+		writeJson(w, http.StatusCreated, commit)
+	case r.match(http.MethodGet, "projects", "*", "repository", "commits"):
+		b := p.ref(r.URL.Query().Get("ref_name"))
+		commits := make([]*types.Commit, 0)
+		if nil != b {
+			commits = b.commits
+		}
+		start, end := paginate(w, r, len(commits))
+		writeJson(w, http.StatusOK, commits[start:end])
+	case r.match(http.MethodGet, "projects", "*", "repository", "tree"):
+		s.serveTree(w, r, p)
+	case r.match(http.MethodGet, "projects", "*", "repository", "*") && strings.HasPrefix(r.segs[3], "archive"):
+		s.serveArchive(w, r, p, strings.TrimPrefix(strings.TrimPrefix(r.segs[3], "archive"), "."))
+	case r.match(http.MethodPost, "projects", "*", "repository", "files", "**"):
+		s.serveFile(w, r, p, types.CommitActionCreate)
+	case r.match(http.MethodPut, "projects", "*", "repository", "files", "**"):
+		s.serveFile(w, r, p, types.CommitActionUpdate)
+	case r.match(http.MethodDelete, "projects", "*", "repository", "files", "**"):
+		s.serveFile(w, r, p, types.CommitActionDelete)
+	default:
+		writeErr(w, http.StatusNotFound, "404 Not Found")
+	}
+}
+
+func (s *Server) serveFile(w http.ResponseWriter, r *request, p *project, action string) {
+	var file types.RepoFile
+	if err := decodeBody(r, &file); nil != err {
+		writeErr(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	fpath := r.rest(4)
+	_, msg := s.commit(p, &types.CommitPayload{
+		CommitBasicInfo: file.CommitBasicInfo,
+		Actions: []*types.CommitAction{{
+			Action:        action,
+			FilePath:      fpath,
+			CommitContent: file.CommitContent,
+		}},
+	})
+	if msg != "" {
+		writeErr(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	if action == types.CommitActionDelete {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	status := http.StatusOK
+	if action == types.CommitActionCreate {
+		status = http.StatusCreated
+	}
+	writeJson(w, status, &types.SavedRepoFile{
+		FileName: fpath,
+		Branch:   file.Branch,
+	})
+}
+
+// commit applies actions of payload on the branch and returns the new commit or error message
+func (s *Server) commit(p *project, payload *types.CommitPayload) (*types.Commit, string) {
+	if payload.Branch == "" {
+		return nil, "branch is missing"
+	}
+	if payload.CommitMessage == "" {
+		return nil, "commit_message is missing"
+	}
+
+	b, ok := p.branches[payload.Branch]
+	switch {
+	case ok:
+		b = b.clone()
+	case payload.StartBranch != "":
+		start, ok := p.branches[payload.StartBranch]
+		if !ok {
+			return nil, fmt.Sprintf("Cannot find start_branch '%s'", payload.StartBranch)
+		}
+		b = start.clone()
+	case len(p.branches) == 0:
+		b = &branch{files: make(map[string]string)}
+	default:
+		return nil, "You can only create or edit files when you are on a branch"
+	}
+
+	for _, a := range payload.Actions {
+		if msg := applyAction(b, a); msg != "" {
+			return nil, msg
+		}
+	}
+
+	commit := &types.Commit{
+		Title:       strings.SplitN(payload.CommitMessage, "\n", 2)[0],
+		Message:     payload.CommitMessage,
+		AuthorName:  payload.AuthorName,
+		AuthorEmail: payload.AuthorEmail,
+		CreatedAt:   time.Now().UTC(),
+		ParentIds:   make([]string, 0),
+	}
+	if commit.AuthorName == "" {
+		commit.AuthorName = s.user.Name
+	}
+	if head := b.head(); nil != head {
+		commit.ParentIds = append(commit.ParentIds, head.Id)
+	}
+	sum := sha1.Sum([]byte(fmt.Sprint(p.Id, payload.Branch, s.nextId(), payload.CommitMessage)))
+	commit.Id = hex.EncodeToString(sum[:])
+	commit.ShortId = commit.Id[:8]
+	b.commits = append([]*types.Commit{commit}, b.commits...)
+
+	p.branches[payload.Branch] = b
+	p.commits[commit.Id] = b
+	if p.DefaultBranch == "" {
+		p.DefaultBranch = payload.Branch
+	}
+
+	return commit, ""
+}
+
+func applyAction(b *branch, a *types.CommitAction) string {
+	content := a.Content
+	if a.Encoding == types.CommitContentEncodingBase64 {
+		data, err := base64.StdEncoding.DecodeString(a.Content)
+		if nil != err {
+			return fmt.Sprintf("Invalid base64 content of '%s'", a.FilePath)
+		}
+		content = string(data)
+	}
+
+	_, exists := b.files[a.FilePath]
+	switch a.Action {
+	case types.CommitActionCreate:
+		if exists {
+			return "A file with this name already exists"
+		}
+		b.files[a.FilePath] = content
+	case types.CommitActionUpdate:
+		if !exists {
+			return "A file with this name doesn't exist"
+		}
+		b.files[a.FilePath] = content
+	case types.CommitActionDelete:
+		if !exists {
+			return "A file with this name doesn't exist"
+		}
+		delete(b.files, a.FilePath)
+	case types.CommitActionMove:
+		old, ok := b.files[a.PreviousPath]
+		if !ok {
+			return "A file with this name doesn't exist"
+		}
+		if exists {
+			return "A file with this name already exists"
+		}
+		delete(b.files, a.PreviousPath)
+		if a.Content == "" {
+			content = old
+		}
+		b.files[a.FilePath] = content
+	default:
+		return fmt.Sprintf("Invalid action '%s'", a.Action)
+	}
+
+	return ""
+}
+
+func (s *Server) serveTree(w http.ResponseWriter, r *request, p *project) {
+	q := r.URL.Query()
+	b := p.ref(q.Get("ref"))
+	if nil == b {
+		writeNotFound(w, "Tree")
+		return
+	}
+
+	dir := strings.Trim(q.Get("path"), "/")
+	recursive := q.Get("recursive") == "true"
+	objs := make(map[string]*types.RepoTreeObj)
+	for fpath, content := range b.files {
+		rel := fpath
+		if dir != "" {
+			if !strings.HasPrefix(fpath, dir+"/") {
+				continue
+			}
+			rel = strings.TrimPrefix(fpath, dir+"/")
+		}
+
+		parts := strings.Split(rel, "/")
+		if !recursive {
+			parts = parts[:1]
+		}
+		for i := range parts {
+			objPath := path.Join(dir, strings.Join(parts[:i+1], "/"))
+			obj := &types.RepoTreeObj{
+				Name: parts[i],
+				Path: objPath,
+				Type: types.TreeObjTypeTree,
+				Mode: "040000",
+			}
+			if objPath == fpath {
+				obj.Type = types.TreeObjTypeBlob
+				obj.Mode = "100644"
+				sum := sha1.Sum([]byte(content))
+				obj.Id = hex.EncodeToString(sum[:])
+			} else {
+				sum := sha1.Sum([]byte(objPath))
+				obj.Id = hex.EncodeToString(sum[:])
+			}
+			objs[objPath] = obj
+		}
+	}
+	if dir != "" && len(objs) == 0 {
+		writeNotFound(w, "Tree")
+		return
+	}
+
+	tree := make([]*types.RepoTreeObj, 0, len(objs))
+	for _, obj := range objs {
+		tree = append(tree, obj)
+	}
+	sort.Slice(tree, func(i, j int) bool {
+		if tree[i].Type != tree[j].Type {
+			return tree[i].Type == types.TreeObjTypeTree
+		}
+		return tree[i].Path < tree[j].Path
+	})
+
+	start, end := paginate(w, r, len(tree))
+	writeJson(w, http.StatusOK, tree[start:end])
+}
+
+// serveArchive writes archive in format of tar, tar.gz (default) or zip
+func (s *Server) serveArchive(w http.ResponseWriter, r *request, p *project, format string) {
+	if format == "" {
+		format = types.ArchiveFormatTarGz
+	}
+	if format != types.ArchiveFormatTarGz && format != types.ArchiveFormatTar && format != types.ArchiveFormatZip {
+		writeErr(w, http.StatusNotFound, "404 Not Found")
+		return
+	}
+
+	q := r.URL.Query()
+	b := p.ref(q.Get("sha"))
+	if nil == b {
+		writeNotFound(w, "Ref")
+		return
+	}
+
+	dir := strings.Trim(q.Get("path"), "/")
+	fpaths := make([]string, 0, len(b.files))
+	for fpath := range b.files {
+		if dir == "" || strings.HasPrefix(fpath, dir+"/") {
+			fpaths = append(fpaths, fpath)
+		}
+	}
+	sort.Strings(fpaths)
+
+	prefix := fmt.Sprintf("%s-%s", p.Path, b.head().Id)
+	if dir != "" {
+		prefix += "-" + strings.Replace(dir, "/", "-", -1)
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, prefix, format))
+
+	if format == types.ArchiveFormatZip {
+		zw := zip.NewWriter(w)
+		for _, fpath := range fpaths {
+			fw, _ := zw.Create(path.Join(prefix, fpath))
+			fw.Write([]byte(b.files[fpath]))
+		}
+		zw.Close()
+		return
+	}
+
+	var out io.Writer = w
+	if format == types.ArchiveFormatTarGz {
+		gw := gzip.NewWriter(w)
+		defer gw.Close()
+		out = gw
+	}
+	tw := tar.NewWriter(out)
+	for _, fpath := range fpaths {
+		content := b.files[fpath]
+		tw.WriteHeader(&tar.Header{
+			Name:    path.Join(prefix, fpath),
+			Mode:    0644,
+			Size:    int64(len(content)),
+			ModTime: b.head().CreatedAt,
+		})
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+}
@@ -0,0 +1,198 @@
+// Package gitlabtest provides an in-memory GitLab API server for testing code using the gitlab client.
+package gitlabtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/haborhuang/go-tools/clients/gitlab"
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+const apiPrefix = "/api/v4"
+
+// Server is a fake GitLab server which keeps groups, projects and repositories in memory.
+// Only the endpoints used by the gitlab client are implemented.
+type Server struct {
+	URL string
+
+	srv  *httptest.Server
+	lock sync.Mutex
+
+	// Id generator shared by all entities
+	lastId   int
+	user     *types.User
+	userNs   *types.Namespace
+	groups   map[int]*types.Group
+	projects map[int]*project
+}
+
+// NewServer starts a fake GitLab server. It should be closed by Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		groups:   make(map[int]*types.Group),
+		projects: make(map[int]*project),
+	}
+	s.user = &types.User{
+		Id:       s.nextId(),
+		Username: "root",
+		Name:     "Administrator",
+		State:    "active",
+	}
+	s.userNs = &types.Namespace{
+		Id:       s.nextId(),
+		Name:     s.user.Username,
+		Path:     s.user.Username,
+		Kind:     types.NamespaceKindUser,
+		FullPath: s.user.Username,
+	}
+
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Config returns the configuration of client to access the server
+func (s *Server) Config() gitlab.Config {
+	return gitlab.Config{
+		Url:   s.URL,
+		Token: "gitlabtest",
+	}
+}
+
+// NewClient returns a client accessing the server
+func (s *Server) NewClient() *gitlab.Client {
+	return gitlab.NewClientOrDie(s.Config())
+}
+
+func (s *Server) nextId() int {
+	s.lastId++
+	return s.lastId
+}
+
+// request is a parsed API request
+type request struct {
+	*http.Request
+	// Unescaped path segments after API prefix
+	segs []string
+}
+
+// match reports whether the segments of request match the pattern.
+// A "*" in pattern matches any single segment and a trailing "**" matches the rest segments.
+func (r *request) match(method string, pattern ...string) bool {
+	if r.Method != method {
+		return false
+	}
+
+	for i, p := range pattern {
+		if p == "**" {
+			return len(r.segs) > i
+		}
+		if i >= len(r.segs) || (p != "*" && p != r.segs[i]) {
+			return false
+		}
+	}
+
+	return len(pattern) == len(r.segs)
+}
+
+// rest joins segments from index i
+func (r *request) rest(i int) string {
+	return strings.Join(r.segs[i:], "/")
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	p := r.URL.EscapedPath()
+	if !strings.HasPrefix(p, apiPrefix+"/") {
+		writeErr(w, http.StatusNotFound, "404 Not Found")
+		return
+	}
+
+	req := &request{Request: r}
+	for _, seg := range strings.Split(strings.Trim(strings.TrimPrefix(p, apiPrefix), "/"), "/") {
+		seg, err := url.PathUnescape(seg)
+		if nil != err {
+			writeErr(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		req.segs = append(req.segs, seg)
+	}
+
+	switch req.segs[0] {
+	case "groups":
+		s.serveGroups(w, req)
+	case "projects":
+		s.serveProjects(w, req)
+	default:
+		writeErr(w, http.StatusNotFound, "404 Not Found")
+	}
+}
+
+func writeJson(w http.ResponseWriter, status int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(obj)
+}
+
+func writeErr(w http.ResponseWriter, status int, msg string) {
+	writeJson(w, status, types.Error{ErrMsg: msg})
+}
+
+func writeNotFound(w http.ResponseWriter, resource string) {
+	writeErr(w, http.StatusNotFound, fmt.Sprintf("404 %s Not Found", resource))
+}
+
+func decodeBody(r *request, obj interface{}) error {
+	if r.Body == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(obj); nil != err {
+		return fmt.Errorf("Decode request body error: %v", err)
+	}
+
+	return nil
+}
+
+// paginate writes pagination headers and returns the range of items for the requested page
+func paginate(w http.ResponseWriter, r *request, total int) (int, int) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page <= 0 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage <= 0 {
+		perPage = 20
+	}
+
+	pages := (total + perPage - 1) / perPage
+	if pages == 0 {
+		pages = 1
+	}
+	w.Header().Set(types.RespHeaderTotalPages, strconv.Itoa(pages))
+	w.Header().Set(types.RespHeaderCurPage, strconv.Itoa(page))
+
+	start := (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+
+	return start, end
+}
@@ -0,0 +1,275 @@
+package gitlabtest
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+func TestCommitAndTree(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	g, err := c.CreateGroup(&types.Group{Name: "demo", Path: "demo"})
+	if nil != err {
+		t.Fatalf("Create group error: %v", err)
+	}
+	p, err := c.CreateProject(&types.Project{Name: "app", NamespaceId: g.Id})
+	if nil != err {
+		t.Fatalf("Create project error: %v", err)
+	}
+	if p.PathWithNamespace != "demo/app" {
+		t.Fatalf("Unexpected path with namespace '%s'", p.PathWithNamespace)
+	}
+
+	pid := types.UrlEncodedPath("demo", "app")
+	_, err = c.CreateCommit(pid, &types.CommitPayload{
+		CommitBasicInfo: types.CommitBasicInfo{Branch: "master", CommitMessage: "init"},
+		Actions: []*types.CommitAction{
+			{Action: types.CommitActionCreate, FilePath: "README.md", CommitContent: types.CommitContent{Content: "demo"}},
+			{Action: types.CommitActionCreate, FilePath: "cmd/main.go", CommitContent: types.CommitContent{Content: "package main"}},
+		},
+	})
+	if nil != err {
+		t.Fatalf("Create commit error: %v", err)
+	}
+
+	tree, pages, err := c.RepoTree(pid, "", "master", true, nil)
+	if nil != err {
+		t.Fatalf("Get tree error: %v", err)
+	}
+	if pages != 1 || len(tree) != 3 {
+		t.Fatalf("Unexpected tree (%d pages): %+v", pages, tree)
+	}
+	if tree[0].Path != "cmd" || tree[0].Type != types.TreeObjTypeTree {
+		t.Errorf("Unexpected first object %+v", tree[0])
+	}
+
+	err = c.DeleteFile(pid, types.UrlEncodedPath("cmd", "main.go"), &types.RepoFile{
+		CommitBasicInfo: types.CommitBasicInfo{Branch: "master", CommitMessage: "remove main"},
+	})
+	if nil != err {
+		t.Fatalf("Delete file error: %v", err)
+	}
+	_, err = c.UpdateFile(pid, types.UrlEncodedPath("cmd", "main.go"), &types.RepoFile{
+		CommitBasicInfo: types.CommitBasicInfo{Branch: "master", CommitMessage: "update main"},
+	})
+	if nil == err {
+		t.Errorf("Expect error on updating deleted file")
+	}
+
+	commits, err := c.ListCommits(pid, "master", nil)
+	if nil != err {
+		t.Fatalf("List commits error: %v", err)
+	}
+	if len(commits) != 2 || commits[0].ParentIds[0] != commits[1].Id {
+		t.Errorf("Unexpected commits %+v", commits)
+	}
+
+	if _, err := c.GetProject(types.UrlEncodedPath("demo", "none")); !types.IsProjectNotFoundErr(err) {
+		t.Errorf("Expect project not found error but got %v", err)
+	}
+}
+
+func TestPipelines(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	p := s.AddProject(&types.Project{Name: "app"})
+	pid := types.UrlEncodedPath(p.PathWithNamespace)
+	if _, err := c.CreatePipeline(pid, "master"); nil == err {
+		t.Errorf("Expect error on creating pipeline of empty repository")
+	}
+
+	_, err := c.CreateCommit(pid, &types.CommitPayload{
+		CommitBasicInfo: types.CommitBasicInfo{Branch: "master", CommitMessage: "init"},
+		Actions: []*types.CommitAction{
+			{Action: types.CommitActionCreate, FilePath: ".gitlab-ci.yml", CommitContent: types.CommitContent{Content: "test: {}"}},
+		},
+	})
+	if nil != err {
+		t.Fatalf("Create commit error: %v", err)
+	}
+
+	pl, err := c.CreatePipeline(pid, "master")
+	if nil != err {
+		t.Fatalf("Create pipeline error: %v", err)
+	}
+	if pl.Finished() {
+		t.Errorf("New pipeline should not be finished")
+	}
+
+	if err := s.SetPipelineStatus(p.PathWithNamespace, pl.Id, "success"); nil != err {
+		t.Fatal(err)
+	}
+	pl, err = c.GetPipeline(pid, pl.Id)
+	if nil != err {
+		t.Fatalf("Get pipeline error: %v", err)
+	}
+	if !pl.Finished() || pl.FinishedAt == nil {
+		t.Errorf("Pipeline should be finished: %+v", pl)
+	}
+}
+
+func TestUpdateProject(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	p, err := c.CreateProject(&types.Project{Name: "app"})
+	if nil != err {
+		t.Fatalf("Create project error: %v", err)
+	}
+	pid := strconv.Itoa(p.Id)
+	if _, err := c.UpdateProject(pid, &types.UpdateProjectOpts{SharedRunners: types.Bool(true)}); nil != err {
+		t.Fatalf("Update project error: %v", err)
+	}
+
+	p, err = c.UpdateProject(pid, &types.UpdateProjectOpts{Description: types.String("demo")})
+	if nil != err {
+		t.Fatalf("Update project error: %v", err)
+	}
+	if p.Description != "demo" || !p.SharedRunners {
+		t.Errorf("Unexpected project after partial update %+v", p)
+	}
+}
+
+func TestAddInvalidGroup(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	if g := s.AddGroup(&types.Group{}); nil != g {
+		t.Errorf("Expect nil for group without path but got %+v", g)
+	}
+	if g := s.AddGroup(&types.Group{Path: "sub", ParentId: 1000}); nil != g {
+		t.Errorf("Expect nil for group without parent but got %+v", g)
+	}
+}
+
+func TestRefs(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	p := s.AddProject(&types.Project{Name: "app"})
+	pid := types.UrlEncodedPath(p.PathWithNamespace)
+	first, err := c.CreateCommit(pid, &types.CommitPayload{
+		CommitBasicInfo: types.CommitBasicInfo{Branch: "master", CommitMessage: "init"},
+		Actions: []*types.CommitAction{
+			{Action: types.CommitActionCreate, FilePath: "README.md", CommitContent: types.CommitContent{Content: "v1"}},
+		},
+	})
+	if nil != err {
+		t.Fatalf("Create commit error: %v", err)
+	}
+	if nil == s.AddTag(p.PathWithNamespace, "v1", "master") {
+		t.Fatalf("Add tag error")
+	}
+	_, err = c.CreateCommit(pid, &types.CommitPayload{
+		CommitBasicInfo: types.CommitBasicInfo{Branch: "master", CommitMessage: "add main"},
+		Actions: []*types.CommitAction{
+			{Action: types.CommitActionCreate, FilePath: "main.go", CommitContent: types.CommitContent{Content: "package main"}},
+		},
+	})
+	if nil != err {
+		t.Fatalf("Create commit error: %v", err)
+	}
+
+	for _, ref := range []string{"v1", first.Id, first.ShortId} {
+		tree, _, err := c.RepoTree(pid, "", ref, false, nil)
+		if nil != err || len(tree) != 1 || tree[0].Path != "README.md" {
+			t.Errorf("Unexpected tree of '%s' %+v, error: %v", ref, tree, err)
+		}
+
+		commits, err := c.ListCommits(pid, ref, nil)
+		if nil != err || len(commits) != 1 || commits[0].Id != first.Id {
+			t.Errorf("Unexpected commits of '%s' %+v, error: %v", ref, commits, err)
+		}
+
+		name, rc, err := c.RepoArchive(pid, ref)
+		if nil != err {
+			t.Errorf("Get archive of '%s' error: %v", ref, err)
+			continue
+		}
+		rc.Close()
+		if name != "app-"+first.Id+".tar.gz" {
+			t.Errorf("Unexpected archive name '%s' of '%s'", name, ref)
+		}
+	}
+
+	if _, _, err := c.RepoTree(pid, "", "none", false, nil); !types.IsNotFoundErr(err) {
+		t.Errorf("Expect not found error but got %v", err)
+	}
+}
+
+func TestProjectActions(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	p := s.AddProject(&types.Project{Name: "app"})
+	g := s.AddGroup(&types.Group{Name: "demo", Path: "demo"})
+	pid := strconv.Itoa(p.Id)
+
+	if err := c.StarProject(pid); nil != err {
+		t.Fatalf("Star project error: %v", err)
+	}
+	if err := c.StarProject(pid); nil != err {
+		t.Fatalf("Star project again error: %v", err)
+	}
+	if np, _ := c.GetProject(pid); np.StarCount != 1 {
+		t.Errorf("Expect 1 star, got %d", np.StarCount)
+	}
+	if err := c.UnstarProject(pid); nil != err {
+		t.Fatalf("Unstar project error: %v", err)
+	}
+
+	fork, err := c.ForkProject(pid, g.FullPath)
+	if nil != err {
+		t.Fatalf("Fork project error: %v", err)
+	}
+	if fork.PathWithNamespace != "demo/app" || nil == fork.ForkedFromProject || fork.ForkedFromProject.Id != p.Id {
+		t.Errorf("Unexpected fork %+v", fork)
+	}
+	if _, err := c.ForkProject(pid, g.FullPath); nil == err {
+		t.Errorf("Expect error on forking into the same namespace again")
+	}
+	if np, _ := c.GetProject(pid); np.ForksCount != 1 || np.StarCount != 0 {
+		t.Errorf("Unexpected project after fork and unstar %+v", np)
+	}
+
+	np, err := c.TransferProject(strconv.Itoa(fork.Id), "")
+	if nil == err {
+		t.Errorf("Expect error on transferring into namespace with the same path, got %+v", np)
+	}
+	if err := c.DeleteProject(pid); nil != err {
+		t.Fatalf("Delete project error: %v", err)
+	}
+	np, err = c.TransferProject(strconv.Itoa(fork.Id), "")
+	if nil != err || np.PathWithNamespace != "root/app" {
+		t.Errorf("Unexpected transferred project %+v, error: %v", np, err)
+	}
+}
+
+func TestMissingId(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	for _, m := range []string{http.MethodPut, http.MethodDelete} {
+		for _, res := range []string{"projects", "groups"} {
+			req, _ := http.NewRequest(m, s.URL+apiPrefix+"/"+res, nil)
+			resp, err := http.DefaultClient.Do(req)
+			if nil != err {
+				t.Fatalf("%s %s error: %v", m, res, err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusNotFound {
+				t.Errorf("Expect 404 of %s %s, got %d", m, res, resp.StatusCode)
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package gitlab
 
 import (
+	"errors"
 	"net/http"
 	"path"
 	"github.com/haborhuang/go-tools/clients/gitlab/types"
@@ -18,6 +19,22 @@ func projectsOfGroupPath(gid string) string {
 	return path.Join(groupPath(gid), projectsPath)
 }
 
+func subgroupsPath(gid string) string {
+	return path.Join(groupPath(gid), "subgroups")
+}
+
+func groupProjectPath(gid, projId string) string {
+	return path.Join(projectsOfGroupPath(gid), projId)
+}
+
+func groupVariablesPath(gid string) string {
+	return path.Join(groupPath(gid), "variables")
+}
+
+func groupVariablePath(gid, key string) string {
+	return path.Join(groupVariablesPath(gid), key)
+}
+
 func (c *Client) GetGroup(gid string) (*types.Group, error) {
 	var group *types.Group
 	err := c.newResponse(
@@ -27,6 +44,95 @@ func (c *Client) GetGroup(gid string) (*types.Group, error) {
 	return group, err
 }
 
+func (c *Client) ListGroups(opts *types.ListGroupsOpts) ([]*types.Group, int, error) {
+	return c.listGroups(groupsPath, opts)
+}
+
+func (c *Client) ListSubgroups(gid string, opts *types.ListGroupsOpts) ([]*types.Group, int, error) {
+	return c.listGroups(subgroupsPath(gid), opts)
+}
+
+func (c *Client) listGroups(subPath string, opts *types.ListGroupsOpts) ([]*types.Group, int, error) {
+	q, err := opts.ToQuery()
+	if nil != err {
+		return nil, 0, err
+	}
+
+	var res []*types.Group
+	respHeader := make(http.Header)
+	respHeader.Set(types.RespHeaderTotalPages, "")
+	err = c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(subPath).Query(q),
+	).extractRespHeaders(respHeader).intoJson(&res)
+
+	pages, _ := strconv.Atoi(respHeader.Get(types.RespHeaderTotalPages))
+	return res, pages, err
+}
+
+// SkipGroup is used as a return value from WalkGroupFunc to indicate that
+// the subgroups of the group in the call are to be skipped.
+var SkipGroup = errors.New("skip this group")
+
+type WalkGroupFunc func(g *types.Group) error
+
+// WalkSubgroups walks the subgroup tree of the specified group depth-first,
+// calling fn for each descendant group. The specified group itself is not visited.
+func (c *Client) WalkSubgroups(gid string, fn WalkGroupFunc) error {
+	opts := &types.ListGroupsOpts{
+		Pagination: types.Pagination{Page: 1, PerPage: 100},
+	}
+
+	for {
+		groups, pages, err := c.ListSubgroups(gid, opts)
+		if nil != err {
+			return err
+		}
+
+		for _, g := range groups {
+			err := fn(g)
+			if err == SkipGroup {
+				continue
+			}
+			if nil != err {
+				return err
+			}
+
+			if err := c.WalkSubgroups(strconv.Itoa(g.Id), fn); nil != err {
+				return err
+			}
+		}
+
+		if opts.Page >= pages {
+			return nil
+		}
+		opts.Page++
+	}
+}
+
+func (c *Client) CreateGroup(g *types.Group) (*types.Group, error) {
+	var ng *types.Group
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPost).SubPath(groupsPath).JsonBody(g),
+	).intoJson(&ng)
+
+	return ng, err
+}
+
+func (c *Client) UpdateGroup(gid string, g *types.Group) (*types.Group, error) {
+	var ng *types.Group
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPut).RawSubPath(groupPath(gid)).JsonBody(g),
+	).intoJson(&ng)
+
+	return ng, err
+}
+
+func (c *Client) DeleteGroup(gid string) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodDelete).RawSubPath(groupPath(gid)),
+	).do()
+}
+
 func (c *Client) ListProjectsOfGroup(gid string, paging *types.Pagination) ([]*types.Project, int, error) {
 	q := make(url.Values)
 	if nil != paging {
@@ -42,4 +148,53 @@ func (c *Client) ListProjectsOfGroup(gid string, paging *types.Pagination) ([]*t
 
 	pages, _ := strconv.Atoi(respHeader.Get(types.RespHeaderTotalPages))
 	return res, pages, err
-}
\ No newline at end of file
+}
+
+// TransferProjectToGroup moves the specified project into the namespace of the group
+func (c *Client) TransferProjectToGroup(gid, projId string) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodPost).RawSubPath(groupProjectPath(gid, projId)),
+	).do()
+}
+
+func (c *Client) ListGroupVariables(gid string) ([]*types.Variable, error) {
+	var res []*types.Variable
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(groupVariablesPath(gid)),
+	).intoJson(&res)
+
+	return res, err
+}
+
+func (c *Client) GetGroupVariable(gid, key string) (*types.Variable, error) {
+	var v *types.Variable
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(groupVariablePath(gid, key)),
+	).intoJson(&v)
+
+	return v, err
+}
+
+func (c *Client) CreateGroupVariable(gid string, v *types.Variable) (*types.Variable, error) {
+	var nv *types.Variable
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPost).RawSubPath(groupVariablesPath(gid)).JsonBody(v),
+	).intoJson(&nv)
+
+	return nv, err
+}
+
+func (c *Client) UpdateGroupVariable(gid string, v *types.Variable) (*types.Variable, error) {
+	var nv *types.Variable
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPut).RawSubPath(groupVariablePath(gid, v.Key)).JsonBody(v),
+	).intoJson(&nv)
+
+	return nv, err
+}
+
+func (c *Client) DeleteGroupVariable(gid, key string) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodDelete).RawSubPath(groupVariablePath(gid, key)),
+	).do()
+}
@@ -0,0 +1,141 @@
+package gitlab
+
+import (
+	"io"
+	"time"
+
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+// Interface is implemented by Client and can be mocked or decorated by users
+type Interface interface {
+	Projects
+	Groups
+	Commits
+	Pipelines
+	Hooks
+	Repositories
+	Environments
+	Releases
+	Search
+}
+
+var _ Interface = &Client{}
+
+type Projects interface {
+	GetProject(projId string) (*types.Project, error)
+	ListProjects(opts *types.ListProjectsOpts) ([]*types.Project, int, error)
+	SearchProjects(search string, paging *types.Pagination) ([]*types.Project, int, error)
+	CreateProject(p *types.Project) (*types.Project, error)
+	UpdateProject(projId string, opts *types.UpdateProjectOpts) (*types.Project, error)
+	DeleteProject(projId string) error
+	ArchiveProject(projId string) (*types.Project, error)
+	UnarchiveProject(projId string) (*types.Project, error)
+	ForkProject(projId, namespace string) (*types.Project, error)
+	StarProject(projId string) error
+	UnstarProject(projId string) error
+	TransferProject(projId, namespace string) (*types.Project, error)
+	ScheduleExport(projId string) error
+	GetExportStatus(projId string) (*types.ExportStatus, error)
+	WaitForExport(projId string, interval, timeout time.Duration) (*types.ExportStatus, error)
+	DownloadExport(projId string) (string, io.ReadCloser, error)
+	ImportProject(opts *types.ImportProjectOpts, file io.Reader) (*types.ImportStatus, error)
+	GetImportStatus(projId string) (*types.ImportStatus, error)
+	WaitForImport(projId string, interval, timeout time.Duration) (*types.ImportStatus, error)
+}
+
+type Groups interface {
+	GetGroup(gid string) (*types.Group, error)
+	ListGroups(opts *types.ListGroupsOpts) ([]*types.Group, int, error)
+	ListSubgroups(gid string, opts *types.ListGroupsOpts) ([]*types.Group, int, error)
+	WalkSubgroups(gid string, fn WalkGroupFunc) error
+	CreateGroup(g *types.Group) (*types.Group, error)
+	UpdateGroup(gid string, g *types.Group) (*types.Group, error)
+	DeleteGroup(gid string) error
+	ListProjectsOfGroup(gid string, paging *types.Pagination) ([]*types.Project, int, error)
+	TransferProjectToGroup(gid, projId string) error
+	ListGroupVariables(gid string) ([]*types.Variable, error)
+	GetGroupVariable(gid, key string) (*types.Variable, error)
+	CreateGroupVariable(gid string, v *types.Variable) (*types.Variable, error)
+	UpdateGroupVariable(gid string, v *types.Variable) (*types.Variable, error)
+	DeleteGroupVariable(gid, key string) error
+}
+
+type Commits interface {
+	CreateCommit(pid string, payload *types.CommitPayload) (*types.Commit, error)
+	ListCommits(pid, ref string, paging *types.Pagination) ([]*types.Commit, error)
+	SetCommitStatus(pid, sha string, opts *types.SetCommitStatusOpts) (*types.CommitStatus, error)
+	ListCommitStatuses(pid, sha string, opts *types.ListCommitStatusesOpts) ([]*types.CommitStatus, error)
+}
+
+// Pipelines includes pipelines, triggers and CI variables of project
+type Pipelines interface {
+	CreatePipeline(pid, ref string) (*types.Pipeline, error)
+	CreatePipelineWithVariables(pid, ref string, vars []*types.PipelineVariable) (*types.Pipeline, error)
+	ListPipelines(pid string, opts *types.ListPipelinesOpts) ([]*types.PipelineBrief, error)
+	GetPipeline(projId string, pipelineId int) (*types.Pipeline, error)
+	ListTriggers(pid string) ([]*types.Trigger, error)
+	CreateTrigger(pid, description string) (*types.Trigger, error)
+	DeleteTrigger(pid string, triggerId int) error
+	TriggerPipeline(pid, token, ref string, vars map[string]string) (*types.Pipeline, error)
+	ListProjectVariables(pid string) ([]*types.Variable, error)
+	GetProjectVariable(pid, key, envScope string) (*types.Variable, error)
+	CreateProjectVariable(pid string, v *types.Variable) (*types.Variable, error)
+	UpdateProjectVariable(pid string, v *types.Variable) (*types.Variable, error)
+	DeleteProjectVariable(pid, key, envScope string) error
+}
+
+type Hooks interface {
+	AddProjectHook(pid string, hook *types.Hook) (*types.Hook, error)
+	EditProjectHook(pid string, hid int, hook *types.Hook) (*types.Hook, error)
+	ListProjectHooks(pid string) ([]*types.Hook, error)
+}
+
+// Repositories includes repository tree, archive and files
+type Repositories interface {
+	RepoArchive(projId, sha string) (string, io.ReadCloser, error)
+	RepoArchiveWithOpts(projId string, opts *types.ArchiveOpts) (string, io.ReadCloser, error)
+	RepoTree(pid, path, ref string, recursive bool, paging *types.Pagination) ([]*types.RepoTreeObj, int, error)
+	CreateFile(pid, fpath string, file *types.RepoFile) (*types.SavedRepoFile, error)
+	UpdateFile(pid, fpath string, file *types.RepoFile) (*types.SavedRepoFile, error)
+	DeleteFile(pid, fpath string, file *types.RepoFile) error
+}
+
+// Environments includes environments and deployments of project
+type Environments interface {
+	ListEnvironments(pid, name, state string, paging *types.Pagination) ([]*types.Environment, int, error)
+	GetEnvironment(pid string, envId int) (*types.Environment, error)
+	CreateEnvironment(pid string, env *types.Environment) (*types.Environment, error)
+	UpdateEnvironment(pid string, envId int, env *types.Environment) (*types.Environment, error)
+	DeleteEnvironment(pid string, envId int) error
+	StopEnvironment(pid string, envId int) (*types.Environment, error)
+	ListDeployments(pid string, opts *types.ListDeploymentsOpts) ([]*types.Deployment, int, error)
+	GetDeployment(pid string, deployId int) (*types.Deployment, error)
+	CreateDeployment(pid string, opts *types.CreateDeploymentOpts) (*types.Deployment, error)
+	UpdateDeploymentStatus(pid string, deployId int, status string) (*types.Deployment, error)
+}
+
+// Releases includes releases and generic package registry of project
+type Releases interface {
+	ListReleases(pid string, paging *types.Pagination) ([]*types.Release, int, error)
+	GetRelease(pid, tag string) (*types.Release, error)
+	CreateRelease(pid string, opts *types.CreateReleaseOpts) (*types.Release, error)
+	UpdateRelease(pid, tag string, opts *types.UpdateReleaseOpts) (*types.Release, error)
+	DeleteRelease(pid, tag string) error
+	ListReleaseLinks(pid, tag string) ([]*types.ReleaseLink, error)
+	CreateReleaseLink(pid, tag string, link *types.ReleaseLink) (*types.ReleaseLink, error)
+	UpdateReleaseLink(pid, tag string, linkId int, link *types.ReleaseLink) (*types.ReleaseLink, error)
+	DeleteReleaseLink(pid, tag string, linkId int) error
+	UploadPackageFile(pid, name, version, fileName string, content io.Reader) error
+	DownloadPackageFile(pid, name, version, fileName string) (io.ReadCloser, error)
+	PackageFileUrl(pid, name, version, fileName string) string
+}
+
+// Search includes global, group and project search
+type Search interface {
+	SearchForProjects(opts *types.SearchOpts) ([]*types.Project, int, error)
+	SearchForIssues(opts *types.SearchOpts) ([]*types.Issue, int, error)
+	SearchForMergeRequests(opts *types.SearchOpts) ([]*types.MergeRequest, int, error)
+	SearchForBlobs(opts *types.SearchOpts) ([]*types.Blob, int, error)
+	SearchForCommits(opts *types.SearchOpts) ([]*types.CommitResult, int, error)
+}
@@ -6,12 +6,16 @@ import (
 	"fmt"
 
 	"github.com/haborhuang/go-tools/clients/gitlab/types"
+	clienttool "github.com/haborhuang/go-tools/http"
 )
 
 const (
 	createPipelinePathFmt = projectPathFmt + "/pipeline"
 	pipelinesPathFmt = projectPathFmt + "/pipelines"
 	pipelinePathFmt = pipelinesPathFmt + "/%d"
+	triggersPathFmt = projectPathFmt + "/triggers"
+	triggerPathFmt = triggersPathFmt + "/%d"
+	triggerPipelinePathFmt = projectPathFmt + "/trigger/pipeline"
 )
 
 func createPipelinePath(projId string) string {
@@ -26,6 +30,18 @@ func pipelinePath(projId string, pipelineId int) string {
 	return fmt.Sprintf(pipelinePathFmt, projId, pipelineId)
 }
 
+func triggersPath(projId string) string {
+	return fmt.Sprintf(triggersPathFmt, projId)
+}
+
+func triggerPath(projId string, triggerId int) string {
+	return fmt.Sprintf(triggerPathFmt, projId, triggerId)
+}
+
+func triggerPipelinePath(projId string) string {
+	return fmt.Sprintf(triggerPipelinePathFmt, projId)
+}
+
 func (c *Client) CreatePipeline(pid, ref string) (*types.Pipeline, error) {
 	q := url.Values{}
 	q.Set("ref", ref)
@@ -38,6 +54,23 @@ func (c *Client) CreatePipeline(pid, ref string) (*types.Pipeline, error) {
 	return p, err
 }
 
+type createPipelineReq struct {
+	Ref       string                    `json:"ref"`
+	Variables []*types.PipelineVariable `json:"variables,omitempty"`
+}
+
+func (c *Client) CreatePipelineWithVariables(pid, ref string, vars []*types.PipelineVariable) (*types.Pipeline, error) {
+	var p *types.Pipeline
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPost).RawSubPath(createPipelinePath(pid)).JsonBody(createPipelineReq{
+			Ref:       ref,
+			Variables: vars,
+		}),
+	).intoJson(&p)
+
+	return p, err
+}
+
 func (c *Client) ListPipelines(pid string, opts *types.ListPipelinesOpts) ([]*types.PipelineBrief, error) {
 	q, err := opts.ToQuery()
 	if nil != err {
@@ -59,4 +92,51 @@ func (c *Client) GetPipeline(projId string, pipelineId int) (*types.Pipeline, er
 	).intoJson(&p)
 
 	return p, err
-}
\ No newline at end of file
+}
+
+func (c *Client) ListTriggers(pid string) ([]*types.Trigger, error) {
+	var ts []*types.Trigger
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(triggersPath(pid)),
+	).intoJson(&ts)
+
+	return ts, err
+}
+
+type createTriggerReq struct {
+	Description string `json:"description"`
+}
+
+func (c *Client) CreateTrigger(pid, description string) (*types.Trigger, error) {
+	var t *types.Trigger
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPost).RawSubPath(triggersPath(pid)).JsonBody(createTriggerReq{
+			Description: description,
+		}),
+	).intoJson(&t)
+
+	return t, err
+}
+
+func (c *Client) DeleteTrigger(pid string, triggerId int) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodDelete).RawSubPath(triggerPath(pid, triggerId)),
+	).do()
+}
+
+// TriggerPipeline runs a pipeline with the trigger token instead of the token of client
+func (c *Client) TriggerPipeline(pid, token, ref string, vars map[string]string) (*types.Pipeline, error) {
+	form := url.Values{}
+	form.Set("token", token)
+	form.Set("ref", ref)
+	for k, v := range vars {
+		form.Set(fmt.Sprintf("variables[%s]", k), v)
+	}
+
+	var p *types.Pipeline
+	err := c.newResponse(
+		clienttool.NewHttpReq(*c.url).Method(http.MethodPost).RawSubPath(triggerPipelinePath(pid)).FormBody(form),
+	).intoJson(&p)
+
+	return p, err
+}
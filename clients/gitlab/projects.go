@@ -4,6 +4,11 @@ import (
 	"github.com/haborhuang/go-tools/clients/gitlab/types"
 	"net/http"
 	"fmt"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"strconv"
+	"time"
 )
 
 func projectPath(projId string) string {
@@ -13,8 +18,15 @@ func projectPath(projId string) string {
 const (
 	projectsPath = "/projects"
 	projectPathFmt = projectsPath + "/%s"
+	projectActionPathFmt = projectPathFmt + "/%s"
+	projectImportPath = projectsPath + "/import"
 )
 
+// projectActionPath returns path of action on project, e.g. archive, fork, star
+func projectActionPath(projId, action string) string {
+	return fmt.Sprintf(projectActionPathFmt, projId, action)
+}
+
 func (c *Client) GetProject(projId string) (*types.Project, error) {
 	var p *types.Project
 	err := c.newResponse(
@@ -23,6 +35,34 @@ func (c *Client) GetProject(projId string) (*types.Project, error) {
 	return p, err
 }
 
+func (c *Client) ListProjects(opts *types.ListProjectsOpts) ([]*types.Project, int, error) {
+	q, err := opts.ToQuery()
+	if nil != err {
+		return nil, 0, fmt.Errorf("Check list projects parameters error: %v", err)
+	}
+
+	var res []*types.Project
+	respHeader := make(http.Header)
+	respHeader.Set(types.RespHeaderTotalPages, "")
+	err = c.newResponse(
+		c.newRequest().Method(http.MethodGet).SubPath(projectsPath).Query(q),
+	).extractRespHeaders(respHeader).intoJson(&res)
+
+	pages, _ := strconv.Atoi(respHeader.Get(types.RespHeaderTotalPages))
+	return res, pages, err
+}
+
+func (c *Client) SearchProjects(search string, paging *types.Pagination) ([]*types.Project, int, error) {
+	opts := &types.ListProjectsOpts{
+		Search: search,
+	}
+	if nil != paging {
+		opts.Pagination = *paging
+	}
+
+	return c.ListProjects(opts)
+}
+
 func (c *Client) CreateProject(p *types.Project) (*types.Project, error) {
 	var np *types.Project
 	err := c.newResponse(
@@ -30,4 +70,213 @@ func (c *Client) CreateProject(p *types.Project) (*types.Project, error) {
 	).intoJson(&np)
 
 	return np, err
-}
\ No newline at end of file
+}
+
+func (c *Client) UpdateProject(projId string, opts *types.UpdateProjectOpts) (*types.Project, error) {
+	var np *types.Project
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPut).RawSubPath(projectPath(projId)).JsonBody(opts),
+	).intoJson(&np)
+
+	return np, err
+}
+
+func (c *Client) DeleteProject(projId string) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodDelete).RawSubPath(projectPath(projId)),
+	).do()
+}
+
+func (c *Client) ArchiveProject(projId string) (*types.Project, error) {
+	return c.projectAction(projId, "archive", nil)
+}
+
+func (c *Client) UnarchiveProject(projId string) (*types.Project, error) {
+	return c.projectAction(projId, "unarchive", nil)
+}
+
+type namespaceReq struct {
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ForkProject forks the project into the namespace specified by id or path.
+// The project is forked into the current user's namespace if namespace is empty.
+func (c *Client) ForkProject(projId, namespace string) (*types.Project, error) {
+	return c.projectAction(projId, "fork", namespaceReq{Namespace: namespace})
+}
+
+// StarProject stars the project. No error is returned if the project is already starred.
+func (c *Client) StarProject(projId string) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodPost).RawSubPath(projectActionPath(projId, "star")),
+	).do()
+}
+
+// UnstarProject unstars the project. No error is returned if the project is not starred.
+func (c *Client) UnstarProject(projId string) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodPost).RawSubPath(projectActionPath(projId, "unstar")),
+	).do()
+}
+
+// TransferProject moves the project into the namespace specified by id or path
+func (c *Client) TransferProject(projId, namespace string) (*types.Project, error) {
+	var np *types.Project
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPut).RawSubPath(projectActionPath(projId, "transfer")).
+			JsonBody(namespaceReq{Namespace: namespace}),
+	).intoJson(&np)
+
+	return np, err
+}
+
+func (c *Client) projectAction(projId, action string, body interface{}) (*types.Project, error) {
+	r := c.newRequest().Method(http.MethodPost).RawSubPath(projectActionPath(projId, action))
+	if nil != body {
+		r.JsonBody(body)
+	}
+
+	var np *types.Project
+	err := c.newResponse(r).intoJson(&np)
+	return np, err
+}
+
+// ScheduleExport schedules an asynchronous export of the project.
+// Use GetExportStatus or WaitForExport to check whether it has finished.
+func (c *Client) ScheduleExport(projId string) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodPost).RawSubPath(projectActionPath(projId, "export")),
+	).do()
+}
+
+func (c *Client) GetExportStatus(projId string) (*types.ExportStatus, error) {
+	var s *types.ExportStatus
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(projectActionPath(projId, "export")),
+	).intoJson(&s)
+
+	return s, err
+}
+
+// WaitForExport polls the export status every interval until the export finished or timed out
+func (c *Client) WaitForExport(projId string, interval, timeout time.Duration) (*types.ExportStatus, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		s, err := c.GetExportStatus(projId)
+		if nil != err {
+			return nil, err
+		}
+
+		if s.Finished() {
+			return s, nil
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			return s, fmt.Errorf("Wait for export timed out, current status '%s'", s.ExportStatus)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// DownloadExport returns file name and content of the finished export.
+// The content should be closed by caller.
+func (c *Client) DownloadExport(projId string) (string, io.ReadCloser, error) {
+	resp, err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(projectActionPath(projId, "export/download")),
+	).doRaw()
+	if nil != err {
+		return "", nil, err
+	}
+
+	name, err := attachmentName(resp)
+	if nil != err {
+		resp.Body.Close()
+		return "", nil, fmt.Errorf("Parse export name error: %v", err)
+	}
+
+	return name, resp.Body, nil
+}
+
+// ImportProject uploads an exported project file and schedules the import.
+// Use GetImportStatus or WaitForImport to check whether it has finished.
+func (c *Client) ImportProject(opts *types.ImportProjectOpts, file io.Reader) (*types.ImportStatus, error) {
+	if nil == opts || opts.Path == "" {
+		return nil, fmt.Errorf("Missing path of project to import")
+	}
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeImportForm(mw, opts, file))
+	}()
+
+	var s *types.ImportStatus
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPost).SubPath(projectImportPath).
+			SetHeader("Content-Type", mw.FormDataContentType()).Body(pr),
+	).intoJson(&s)
+	pr.Close()
+
+	return s, err
+}
+
+func writeImportForm(mw *multipart.Writer, opts *types.ImportProjectOpts, file io.Reader) error {
+	fields := url.Values{}
+	fields.Set("path", opts.Path)
+	if opts.Name != "" {
+		fields.Set("name", opts.Name)
+	}
+	if opts.Namespace != "" {
+		fields.Set("namespace", opts.Namespace)
+	}
+	if opts.Overwrite {
+		fields.Set("overwrite", "true")
+	}
+	for k := range fields {
+		if err := mw.WriteField(k, fields.Get(k)); nil != err {
+			return err
+		}
+	}
+
+	fw, err := mw.CreateFormFile("file", opts.Path+".tar.gz")
+	if nil != err {
+		return err
+	}
+	if _, err := io.Copy(fw, file); nil != err {
+		return err
+	}
+
+	return mw.Close()
+}
+
+func (c *Client) GetImportStatus(projId string) (*types.ImportStatus, error) {
+	var s *types.ImportStatus
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(projectActionPath(projId, "import")),
+	).intoJson(&s)
+
+	return s, err
+}
+
+// WaitForImport polls the import status every interval until the import finished, failed or timed out
+func (c *Client) WaitForImport(projId string, interval, timeout time.Duration) (*types.ImportStatus, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		s, err := c.GetImportStatus(projId)
+		if nil != err {
+			return nil, err
+		}
+
+		if s.ImportStatus == types.ImportStatusFailed {
+			return s, fmt.Errorf("Import failed: %s", s.ImportError)
+		}
+		if s.Finished() {
+			return s, nil
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			return s, fmt.Errorf("Wait for import timed out, current status '%s'", s.ImportStatus)
+		}
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,156 @@
+package gitlab
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+const (
+	releasesPathFmt     = projectPathFmt + "/releases"
+	releasePathFmt      = releasesPathFmt + "/%s"
+	releaseLinksPathFmt = releasePathFmt + "/assets/links"
+	releaseLinkPathFmt  = releaseLinksPathFmt + "/%d"
+	genericPkgPathFmt   = projectPathFmt + "/packages/generic/%s/%s/%s"
+)
+
+func releasesPath(projId string) string {
+	return fmt.Sprintf(releasesPathFmt, projId)
+}
+
+func releasePath(projId, tag string) string {
+	return fmt.Sprintf(releasePathFmt, projId, url.PathEscape(tag))
+}
+
+func releaseLinksPath(projId, tag string) string {
+	return fmt.Sprintf(releaseLinksPathFmt, projId, url.PathEscape(tag))
+}
+
+func releaseLinkPath(projId, tag string, linkId int) string {
+	return fmt.Sprintf(releaseLinkPathFmt, projId, url.PathEscape(tag), linkId)
+}
+
+func genericPkgPath(projId, name, version, fileName string) string {
+	return fmt.Sprintf(genericPkgPathFmt, projId, url.PathEscape(name), url.PathEscape(version), url.PathEscape(fileName))
+}
+
+func (c *Client) ListReleases(pid string, paging *types.Pagination) ([]*types.Release, int, error) {
+	q := make(url.Values)
+	if nil != paging {
+		paging.ToQuery(q)
+	}
+
+	var res []*types.Release
+	respHeader := make(http.Header)
+	respHeader.Set(types.RespHeaderTotalPages, "")
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(releasesPath(pid)).Query(q),
+	).extractRespHeaders(respHeader).intoJson(&res)
+
+	pages, _ := strconv.Atoi(respHeader.Get(types.RespHeaderTotalPages))
+	return res, pages, err
+}
+
+func (c *Client) GetRelease(pid, tag string) (*types.Release, error) {
+	var r *types.Release
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(releasePath(pid, tag)),
+	).intoJson(&r)
+
+	return r, err
+}
+
+func (c *Client) CreateRelease(pid string, opts *types.CreateReleaseOpts) (*types.Release, error) {
+	if nil == opts || opts.TagName == "" {
+		return nil, fmt.Errorf("Missing tag name of release")
+	}
+
+	var r *types.Release
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPost).RawSubPath(releasesPath(pid)).JsonBody(opts),
+	).intoJson(&r)
+
+	return r, err
+}
+
+func (c *Client) UpdateRelease(pid, tag string, opts *types.UpdateReleaseOpts) (*types.Release, error) {
+	var r *types.Release
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPut).RawSubPath(releasePath(pid, tag)).JsonBody(opts),
+	).intoJson(&r)
+
+	return r, err
+}
+
+// DeleteRelease deletes the release but keeps the tag
+func (c *Client) DeleteRelease(pid, tag string) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodDelete).RawSubPath(releasePath(pid, tag)),
+	).do()
+}
+
+func (c *Client) ListReleaseLinks(pid, tag string) ([]*types.ReleaseLink, error) {
+	var res []*types.ReleaseLink
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(releaseLinksPath(pid, tag)),
+	).intoJson(&res)
+
+	return res, err
+}
+
+func (c *Client) CreateReleaseLink(pid, tag string, link *types.ReleaseLink) (*types.ReleaseLink, error) {
+	var nl *types.ReleaseLink
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPost).RawSubPath(releaseLinksPath(pid, tag)).JsonBody(link),
+	).intoJson(&nl)
+
+	return nl, err
+}
+
+func (c *Client) UpdateReleaseLink(pid, tag string, linkId int, link *types.ReleaseLink) (*types.ReleaseLink, error) {
+	var nl *types.ReleaseLink
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPut).RawSubPath(releaseLinkPath(pid, tag, linkId)).JsonBody(link),
+	).intoJson(&nl)
+
+	return nl, err
+}
+
+func (c *Client) DeleteReleaseLink(pid, tag string, linkId int) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodDelete).RawSubPath(releaseLinkPath(pid, tag, linkId)),
+	).do()
+}
+
+// UploadPackageFile streams content into the generic package registry
+func (c *Client) UploadPackageFile(pid, name, version, fileName string, content io.Reader) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodPut).RawSubPath(genericPkgPath(pid, name, version, fileName)).
+			SetHeader("Content-Type", "application/octet-stream").Body(content),
+	).do()
+}
+
+// DownloadPackageFile returns content of the file in the generic package registry.
+// The content should be closed by caller.
+func (c *Client) DownloadPackageFile(pid, name, version, fileName string) (io.ReadCloser, error) {
+	resp, err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(genericPkgPath(pid, name, version, fileName)),
+	).doRaw()
+	if nil != err {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// PackageFileUrl returns URL of the file in the generic package registry, e.g. to be used as release link
+func (c *Client) PackageFileUrl(pid, name, version, fileName string) string {
+	u := *c.url
+	u.RawPath = u.Path + genericPkgPath(pid, name, version, fileName)
+	u.Path, _ = url.PathUnescape(u.RawPath)
+	return u.String()
+}
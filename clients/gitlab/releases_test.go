@@ -0,0 +1,46 @@
+package gitlab_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haborhuang/go-tools/clients/gitlab"
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+func TestReleaseLinkExternal(t *testing.T) {
+	var body map[string]interface{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		if err := json.NewDecoder(r.Body).Decode(&body); nil != err {
+			t.Errorf("Decode body error: %v", err)
+		}
+		w.Write([]byte(`{"id":1,"name":"bin","external":false}`))
+	}))
+	defer s.Close()
+	c := gitlab.NewClientOrDie(gitlab.Config{Url: s.URL})
+
+	cases := []struct {
+		link     *types.ReleaseLink
+		external interface{}
+		sent     bool
+	}{
+		{&types.ReleaseLink{Name: "bin"}, nil, false},
+		{&types.ReleaseLink{External: types.Bool(true)}, true, true},
+		{&types.ReleaseLink{External: types.Bool(false)}, false, true},
+	}
+	for _, tc := range cases {
+		link, err := c.UpdateReleaseLink("1", "v1.0", 1, tc.link)
+		if nil != err {
+			t.Fatalf("Update release link error: %v", err)
+		}
+		if external, sent := body["external"]; sent != tc.sent || external != tc.external {
+			t.Errorf("Expect external %v sent %v, got body %v", tc.external, tc.sent, body)
+		}
+		if nil == link.External || *link.External {
+			t.Errorf("Unexpected external of link %+v", link)
+		}
+	}
+}
@@ -4,40 +4,50 @@ import (
 	"io"
 	"net/url"
 	"fmt"
-	"mime"
 	"github.com/haborhuang/go-tools/clients/gitlab/types"
 	"net/http"
 	"strconv"
 )
 
-func repoArchPath(projId string) string {
-	return fmt.Sprintf(repoArchPathFmt, projId)
+func repoArchPath(projId, format string) string {
+	if format == "" {
+		return fmt.Sprintf(repoArchPathFmt, projId)
+	}
+	return fmt.Sprintf(repoArchPathFmt, projId) + "." + format
 }
 
 const repoArchPathFmt = projectPathFmt + "/repository/archive"
 
 func (c *Client) RepoArchive(projId, sha string) (string, io.ReadCloser, error) {
-	r := c.newRequest().
-		RawSubPath(repoArchPath(projId))
-	if sha != "" {
-		q := make(url.Values)
-		q.Set("sha", sha)
-		r.Query(q)
+	return c.RepoArchiveWithOpts(projId, &types.ArchiveOpts{SHA: sha})
+}
+
+// RepoArchiveWithOpts returns file name and content of the archive.
+// The content should be closed by caller. Use ExtractArchive to extract it.
+func (c *Client) RepoArchiveWithOpts(projId string, opts *types.ArchiveOpts) (string, io.ReadCloser, error) {
+	if err := opts.Check(); nil != err {
+		return "", nil, err
 	}
 
+	format := ""
+	if nil != opts {
+		format = opts.Format
+	}
 
-	resp, err := c.newResponse(r).doRaw()
+	resp, err := c.newResponse(
+		c.newRequest().RawSubPath(repoArchPath(projId, format)).Query(opts.ToQuery()),
+	).doRaw()
 	if nil != err {
 		return "", nil, err
 	}
 
-	_, params, err := mime.ParseMediaType(resp.Header.Get("content-disposition"))
+	name, err := attachmentName(resp)
 	if nil != err {
 		resp.Body.Close()
 		return "", nil, fmt.Errorf("Parse archive name error: %v", err)
 	}
 
-	return params["filename"], resp.Body, nil
+	return name, resp.Body, nil
 }
 
 func repoTreePath(projId string) string {
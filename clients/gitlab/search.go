@@ -0,0 +1,71 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+const searchPath = "/search"
+
+func scopedSearchPath(opts *types.SearchOpts) string {
+	switch {
+	case opts.GroupId != "":
+		return groupPath(opts.GroupId) + searchPath
+	case opts.ProjectId != "":
+		return projectPath(opts.ProjectId) + searchPath
+	}
+
+	return searchPath
+}
+
+// SearchForProjects searches projects globally or within group. Use SearchProjects to filter all accessible projects.
+func (c *Client) SearchForProjects(opts *types.SearchOpts) ([]*types.Project, int, error) {
+	var res []*types.Project
+	pages, err := c.search(types.SearchScopeProjects, opts, &res)
+	return res, pages, err
+}
+
+func (c *Client) SearchForIssues(opts *types.SearchOpts) ([]*types.Issue, int, error) {
+	var res []*types.Issue
+	pages, err := c.search(types.SearchScopeIssues, opts, &res)
+	return res, pages, err
+}
+
+func (c *Client) SearchForMergeRequests(opts *types.SearchOpts) ([]*types.MergeRequest, int, error) {
+	var res []*types.MergeRequest
+	pages, err := c.search(types.SearchScopeMergeRequests, opts, &res)
+	return res, pages, err
+}
+
+// SearchForBlobs searches file contents. Global and group search of blobs requires Elasticsearch enabled.
+func (c *Client) SearchForBlobs(opts *types.SearchOpts) ([]*types.Blob, int, error) {
+	var res []*types.Blob
+	pages, err := c.search(types.SearchScopeBlobs, opts, &res)
+	return res, pages, err
+}
+
+// SearchForCommits searches commit messages. Global and group search of commits requires Elasticsearch enabled.
+func (c *Client) SearchForCommits(opts *types.SearchOpts) ([]*types.CommitResult, int, error) {
+	var res []*types.CommitResult
+	pages, err := c.search(types.SearchScopeCommits, opts, &res)
+	return res, pages, err
+}
+
+func (c *Client) search(scope string, opts *types.SearchOpts, expected interface{}) (int, error) {
+	q, err := opts.ToQuery(scope)
+	if nil != err {
+		return 0, fmt.Errorf("Check search parameters error: %v", err)
+	}
+
+	respHeader := make(http.Header)
+	respHeader.Set(types.RespHeaderTotalPages, "")
+	err = c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(scopedSearchPath(opts)).Query(q),
+	).extractRespHeaders(respHeader).intoJson(expected)
+
+	pages, _ := strconv.Atoi(respHeader.Get(types.RespHeaderTotalPages))
+	return pages, err
+}
@@ -0,0 +1,37 @@
+package gitlab_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haborhuang/go-tools/clients/gitlab"
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+func TestSearchForProjects(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/groups/devops/search" {
+			t.Errorf("Unexpected path '%s'", r.URL.EscapedPath())
+		}
+		if q := r.URL.Query(); q.Get("scope") != "projects" || q.Get("search") != "app" {
+			t.Errorf("Unexpected query '%s'", r.URL.RawQuery)
+		}
+		json.NewEncoder(w).Encode([]*types.Project{{Id: 1, Name: "app"}})
+	}))
+	defer s.Close()
+	c := gitlab.NewClientOrDie(gitlab.Config{Url: s.URL})
+
+	res, _, err := c.SearchForProjects(&types.SearchOpts{Search: "app", GroupId: "devops"})
+	if nil != err {
+		t.Fatalf("Search projects error: %v", err)
+	}
+	if len(res) != 1 || res[0].Name != "app" {
+		t.Errorf("Unexpected projects %+v", res)
+	}
+
+	if _, _, err := c.SearchForProjects(&types.SearchOpts{Search: "app", ProjectId: "1"}); nil == err {
+		t.Errorf("Expect error of searching projects within project")
+	}
+}
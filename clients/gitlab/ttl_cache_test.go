@@ -0,0 +1,35 @@
+package gitlab
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTTLCacheSweep(t *testing.T) {
+	c := newTTLCache(10 * time.Millisecond)
+	for i := 1; i <= 100; i++ {
+		c.set(i, i, "path/"+strconv.Itoa(i))
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// Expired objects are swept by set without being read
+	c.set(1000, 1000, "path/1000")
+	if len(c.entries) != 1 || len(c.keys) != 2 {
+		t.Fatalf("Expect only 1 object with 2 keys, got %d objects and %d keys", len(c.entries), len(c.keys))
+	}
+
+	// Reused key is moved to the new object
+	c.set(1001, 1001, "path/1000")
+	if _, ok := c.get("1000"); ok {
+		t.Errorf("Expect object of reused key removed")
+	}
+	if obj, ok := c.get("path/1000"); !ok || obj.(int) != 1001 {
+		t.Errorf("Unexpected object %v of reused key", obj)
+	}
+
+	c.invalidate("path/1000")
+	if len(c.entries) != 0 || len(c.keys) != 0 {
+		t.Errorf("Expect empty cache, got %d objects and %d keys", len(c.entries), len(c.keys))
+	}
+}
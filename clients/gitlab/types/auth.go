@@ -0,0 +1,24 @@
+package types
+
+import "time"
+
+type OAuth2Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	CreatedAt    int64  `json:"created_at"`
+}
+
+// ExpiresAt returns the expiry time, or zero time if the token never expires
+func (t *OAuth2Token) ExpiresAt() time.Time {
+	if t.ExpiresIn <= 0 {
+		return time.Time{}
+	}
+
+	created := time.Now()
+	if t.CreatedAt > 0 {
+		created = time.Unix(t.CreatedAt, 0)
+	}
+	return created.Add(time.Duration(t.ExpiresIn) * time.Second)
+}
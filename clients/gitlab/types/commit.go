@@ -1,6 +1,10 @@
 package types
 
-import "time"
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
 
 type Commit struct {
 	Id          string    `json:"id"`
@@ -49,3 +53,94 @@ type CommitAction struct {
 	PreviousPath string `json:"previous_path,omitempty"`
 	CommitContent
 }
+
+const (
+	CommitStatusPending  = "pending"
+	CommitStatusRunning  = "running"
+	CommitStatusSuccess  = "success"
+	CommitStatusFailed   = "failed"
+	CommitStatusCanceled = "canceled"
+)
+
+var validCommitStatus = map[string]bool{
+	CommitStatusPending:  true,
+	CommitStatusRunning:  true,
+	CommitStatusSuccess:  true,
+	CommitStatusFailed:   true,
+	CommitStatusCanceled: true,
+}
+
+type CommitStatus struct {
+	Id           int        `json:"id"`
+	SHA          string     `json:"sha"`
+	Ref          string     `json:"ref"`
+	Status       string     `json:"status"`
+	Name         string     `json:"name"`
+	TargetUrl    string     `json:"target_url"`
+	Description  string     `json:"description"`
+	Coverage     *float64   `json:"coverage"`
+	AllowFailure bool       `json:"allow_failure"`
+	Author       *User      `json:"author"`
+	CreatedAt    *time.Time `json:"created_at"`
+	StartedAt    *time.Time `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at"`
+}
+
+type SetCommitStatusOpts struct {
+	State string `json:"state"`
+	// Ref (branch or tag) of the commit, required if the commit is referred by several refs
+	Ref string `json:"ref,omitempty"`
+	// Label to differentiate this status from others, "default" if empty
+	Name        string   `json:"name,omitempty"`
+	TargetUrl   string   `json:"target_url,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Coverage    *float64 `json:"coverage,omitempty"`
+	PipelineId  int      `json:"pipeline_id,omitempty"`
+}
+
+func (opts *SetCommitStatusOpts) Check() error {
+	if nil == opts {
+		return fmt.Errorf("Missing commit status")
+	}
+
+	if !validCommitStatus[opts.State] {
+		return fmt.Errorf("Invalid state '%s'", opts.State)
+	}
+
+	return nil
+}
+
+type ListCommitStatusesOpts struct {
+	Ref   string
+	Stage string
+	Name  string
+	// Return all statuses, not only the latest ones
+	All bool
+	Pagination
+}
+
+func (opts *ListCommitStatusesOpts) ToQuery() (url.Values, error) {
+	query := make(url.Values)
+	if nil == opts {
+		return query, nil
+	}
+
+	if err := opts.Pagination.check(); nil != err {
+		return nil, err
+	}
+
+	if "" != opts.Ref {
+		query.Set("ref", opts.Ref)
+	}
+	if "" != opts.Stage {
+		query.Set("stage", opts.Stage)
+	}
+	if "" != opts.Name {
+		query.Set("name", opts.Name)
+	}
+	if opts.All {
+		query.Set("all", "true")
+	}
+	opts.Pagination.ToQuery(query)
+	return query, nil
+}
@@ -4,3 +4,13 @@ const (
 	RespHeaderTotalPages = "X-Total-Pages"
 	RespHeaderCurPage = "X-Page"
 )
+
+// String returns pointer of s, used to set optional fields
+func String(s string) *string {
+	return &s
+}
+
+// Bool returns pointer of b, used to set optional fields
+func Bool(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,125 @@
+package types
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	EnvironmentStateAvailable = "available"
+	EnvironmentStateStopped   = "stopped"
+)
+
+type Environment struct {
+	Id             int         `json:"id,omitempty"`
+	Name           string      `json:"name,omitempty"`
+	Slug           string      `json:"slug,omitempty"`
+	ExternalUrl    string      `json:"external_url,omitempty"`
+	State          string      `json:"state,omitempty"`
+	LastDeployment *Deployment `json:"last_deployment,omitempty"`
+}
+
+const (
+	DeploymentStatusCreated  = "created"
+	DeploymentStatusRunning  = "running"
+	DeploymentStatusSuccess  = "success"
+	DeploymentStatusFailed   = "failed"
+	DeploymentStatusCanceled = "canceled"
+)
+
+type Deployment struct {
+	Id          int          `json:"id"`
+	Iid         int          `json:"iid"`
+	Ref         string       `json:"ref"`
+	SHA         string       `json:"sha"`
+	Status      string       `json:"status"`
+	CreatedAt   *time.Time   `json:"created_at"`
+	UpdatedAt   *time.Time   `json:"updated_at"`
+	User        *User        `json:"user"`
+	Environment *Environment `json:"environment"`
+	// The job which runs the deployment, nil if the deployment is created by API
+	Deployable *Job `json:"deployable"`
+}
+
+// Pipeline returns the pipeline which runs the deployment, or nil if the deployment is created by API
+func (d *Deployment) Pipeline() *PipelineBrief {
+	if nil == d.Deployable {
+		return nil
+	}
+	return &d.Deployable.Pipeline
+}
+
+type CreateDeploymentOpts struct {
+	// Name of the environment
+	Environment string `json:"environment"`
+	SHA         string `json:"sha"`
+	Ref         string `json:"ref"`
+	Tag         bool   `json:"tag"`
+	Status      string `json:"status"`
+}
+
+type ListDeploymentsOpts struct {
+	Environment string
+	Status      string
+	OrderBy     string
+	// "asc" or "desc". GitLab sorts deployments ascending by default.
+	Sort string
+	Pagination
+}
+
+var validDeploymentStatus = map[string]bool{
+	DeploymentStatusCreated:  true,
+	DeploymentStatusRunning:  true,
+	DeploymentStatusSuccess:  true,
+	DeploymentStatusFailed:   true,
+	DeploymentStatusCanceled: true,
+}
+
+var validDeploymentOrder = map[string]bool{
+	"id":         true,
+	"iid":        true,
+	"created_at": true,
+	"updated_at": true,
+	"ref":        true,
+}
+
+func (opts *ListDeploymentsOpts) ToQuery() (url.Values, error) {
+	query := make(url.Values)
+	if nil == opts {
+		return query, nil
+	}
+
+	if "" != opts.Status && !validDeploymentStatus[opts.Status] {
+		return nil, fmt.Errorf("Invalid status '%s'", opts.Status)
+	}
+	if "" != opts.OrderBy && !validDeploymentOrder[opts.OrderBy] {
+		return nil, fmt.Errorf("Invalid order_by '%s'", opts.OrderBy)
+	}
+	if "" != opts.Sort && "asc" != opts.Sort && "desc" != opts.Sort {
+		return nil, fmt.Errorf("Invalid sort '%s'", opts.Sort)
+	}
+	if err := opts.Pagination.check(); nil != err {
+		return nil, err
+	}
+
+	if "" != opts.Environment {
+		query.Set("environment", opts.Environment)
+	}
+	if "" != opts.Status {
+		query.Set("status", opts.Status)
+	}
+	if "" != opts.OrderBy {
+		query.Set("order_by", opts.OrderBy)
+	}
+	if "" != opts.Sort {
+		query.Set("sort", opts.Sort)
+	}
+	opts.Pagination.ToQuery(query)
+	return query, nil
+}
+
+// ValidDeploymentStatus reports whether status can be used to create or update deployment
+func ValidDeploymentStatus(status string) bool {
+	return validDeploymentStatus[status]
+}
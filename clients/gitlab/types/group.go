@@ -1,5 +1,16 @@
 package types
 
+import (
+	"fmt"
+	"net/url"
+)
+
+const (
+	VisibilityPrivate  = "private"
+	VisibilityInternal = "internal"
+	VisibilityPublic   = "public"
+)
+
 type Group struct {
 	Id                        int        `json:"id,omitempty"`
 	Name                      string     `json:"name,omitempty"`
@@ -16,3 +27,60 @@ type Group struct {
 	SharedRunnersMinutesLimit int        `json:"shared_runners_minutes_limit,omitempty"`
 	Projects                  []*Project `json:"projects,omitempty"`
 }
+
+type ListGroupsOpts struct {
+	Search       string
+	AllAvailable bool
+	Owned        bool
+	// Only used for listing subgroups
+	SkipGroups []int
+	OrderBy    string
+	SortDesc   bool
+	Pagination
+}
+
+var validGroupOrder = map[string]bool{
+	"name": true,
+	"path": true,
+	"id":   true,
+}
+
+func (opts *ListGroupsOpts) ToQuery() (url.Values, error) {
+	query := make(url.Values)
+	if nil == opts {
+		return query, nil
+	}
+
+	if err := opts.check(); nil != err {
+		return nil, err
+	}
+
+	if "" != opts.Search {
+		query.Set("search", opts.Search)
+	}
+	if opts.AllAvailable {
+		query.Set("all_available", "true")
+	}
+	if opts.Owned {
+		query.Set("owned", "true")
+	}
+	for _, id := range opts.SkipGroups {
+		query.Add("skip_groups[]", fmt.Sprint(id))
+	}
+	if "" != opts.OrderBy {
+		query.Set("order_by", opts.OrderBy)
+	}
+	if opts.SortDesc {
+		query.Set("sort", "desc")
+	}
+	opts.Pagination.ToQuery(query)
+	return query, nil
+}
+
+func (opts *ListGroupsOpts) check() error {
+	if "" != opts.OrderBy && !validGroupOrder[opts.OrderBy] {
+		return fmt.Errorf("Invalid order_by '%s'", opts.OrderBy)
+	}
+
+	return opts.Pagination.check()
+}
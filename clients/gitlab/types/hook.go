@@ -4,6 +4,8 @@ type Hook struct {
 	Id           int    `json:"id,omitempty"`
 	Url          string `json:"url,omitempty"`
 	CreatedAtRaw string `json:"created_at,omitempty"`
+	// Secret token to validate received payloads, never returned by GitLab
+	Token string `json:"token,omitempty"`
 	HookFlags
 }
 
@@ -1,10 +1,10 @@
 package types
 
 import (
-	"time"
 	"fmt"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 type Pipeline struct {
@@ -168,4 +168,14 @@ func (p Pagination) ToQuery(vals url.Values) {
 	if p.PerPage > 0 {
 		vals.Set("per_page", strconv.Itoa(p.PerPage))
 	}
-}
\ No newline at end of file
+}
+
+type Trigger struct {
+	Id          int        `json:"id"`
+	Description string     `json:"description"`
+	Token       string     `json:"token"`
+	Owner       *User      `json:"owner"`
+	CreatedAt   *time.Time `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+	LastUsed    *time.Time `json:"last_used"`
+}
@@ -1,8 +1,10 @@
 package types
 
 import (
+	"fmt"
 	"net/url"
 	"path"
+	"strconv"
 )
 
 func UrlEncodedPath(args ...string) string {
@@ -30,6 +32,24 @@ type Project struct {
 	HttpRepoUrl          string     `json:"http_url_to_repo"`
 	WebUrl               string     `json:"web_url"`
 	SharedRunners        bool       `json:"shared_runners_enabled"`
+	Archived             bool       `json:"archived,omitempty"`
+	StarCount            int        `json:"star_count,omitempty"`
+	ForksCount           int        `json:"forks_count,omitempty"`
+	ForkedFromProject    *Project   `json:"forked_from_project,omitempty"`
+	LastActivityAtRaw    string     `json:"last_activity_at,omitempty"`
+}
+
+// UpdateProjectOpts is the body of updating project. Only the fields which are not nil are changed.
+type UpdateProjectOpts struct {
+	Name                 *string `json:"name,omitempty"`
+	Path                 *string `json:"path,omitempty"`
+	Description          *string `json:"description,omitempty"`
+	DefaultBranch        *string `json:"default_branch,omitempty"`
+	Visibility           *string `json:"visibility,omitempty"`
+	IssuesEnabled        *bool   `json:"issues_enabled,omitempty"`
+	MergeRequestsEnabled *bool   `json:"merge_requests_enabled,omitempty"`
+	WikiEnabled          *bool   `json:"wiki_enabled,omitempty"`
+	SharedRunners        *bool   `json:"shared_runners_enabled,omitempty"`
 }
 
 type Member struct {
@@ -42,12 +62,159 @@ type Member struct {
 	// AccessLevel int
 }
 
+const (
+	NamespaceKindUser  = "user"
+	NamespaceKindGroup = "group"
+)
+
 type Namespace struct {
 	Id          int    `json:"id"`
 	Name        string `json:"name"`
 	Path        string `json:"path"`
+	Kind        string `json:"kind"`
+	FullPath    string `json:"full_path"`
+	ParentId    int    `json:"parent_id"`
 	Description string `json:"description"`
 	Owner_Id    int    `json:"owner_id"`
 	Created_At  string `json:"created_at"`
 	Updated_At  string `json:"updated_at"`
 }
+
+func (n *Namespace) IsGroup() bool {
+	return n != nil && n.Kind == NamespaceKindGroup
+}
+
+type ListProjectsOpts struct {
+	Search     string
+	Owned      bool
+	Membership bool
+	Starred    bool
+	// Filter by archived status if not nil
+	Archived   *bool
+	Visibility string
+	OrderBy    string
+	SortAsc    bool
+	Simple     bool
+	Pagination
+}
+
+var validProjectOrder = map[string]bool{
+	"id":               true,
+	"name":             true,
+	"path":             true,
+	"created_at":       true,
+	"updated_at":       true,
+	"last_activity_at": true,
+}
+
+var validVisibility = map[string]bool{
+	VisibilityPrivate:  true,
+	VisibilityInternal: true,
+	VisibilityPublic:   true,
+}
+
+func (opts *ListProjectsOpts) ToQuery() (url.Values, error) {
+	query := make(url.Values)
+	if nil == opts {
+		return query, nil
+	}
+
+	if err := opts.check(); nil != err {
+		return nil, err
+	}
+
+	if "" != opts.Search {
+		query.Set("search", opts.Search)
+	}
+	if opts.Owned {
+		query.Set("owned", "true")
+	}
+	if opts.Membership {
+		query.Set("membership", "true")
+	}
+	if opts.Starred {
+		query.Set("starred", "true")
+	}
+	if nil != opts.Archived {
+		query.Set("archived", strconv.FormatBool(*opts.Archived))
+	}
+	if "" != opts.Visibility {
+		query.Set("visibility", opts.Visibility)
+	}
+	if "" != opts.OrderBy {
+		query.Set("order_by", opts.OrderBy)
+	}
+	if opts.SortAsc {
+		query.Set("sort", "asc")
+	}
+	if opts.Simple {
+		query.Set("simple", "true")
+	}
+	opts.Pagination.ToQuery(query)
+	return query, nil
+}
+
+func (opts *ListProjectsOpts) check() error {
+	if "" != opts.Visibility && !validVisibility[opts.Visibility] {
+		return fmt.Errorf("Invalid visibility '%s'", opts.Visibility)
+	}
+
+	if "" != opts.OrderBy && !validProjectOrder[opts.OrderBy] {
+		return fmt.Errorf("Invalid order_by '%s'", opts.OrderBy)
+	}
+
+	return opts.Pagination.check()
+}
+
+const (
+	ExportStatusNone         = "none"
+	ExportStatusQueued       = "queued"
+	ExportStatusStarted      = "started"
+	ExportStatusFinished     = "finished"
+	ExportStatusRegeneration = "regeneration_in_progress"
+)
+
+type ExportStatus struct {
+	Id           int    `json:"id"`
+	Name         string `json:"name"`
+	Path         string `json:"path"`
+	ExportStatus string `json:"export_status"`
+	Links        struct {
+		ApiUrl string `json:"api_url"`
+		WebUrl string `json:"web_url"`
+	} `json:"_links"`
+}
+
+func (s *ExportStatus) Finished() bool {
+	return s.ExportStatus == ExportStatusFinished
+}
+
+const (
+	ImportStatusNone      = "none"
+	ImportStatusScheduled = "scheduled"
+	ImportStatusStarted   = "started"
+	ImportStatusFinished  = "finished"
+	ImportStatusFailed    = "failed"
+)
+
+type ImportStatus struct {
+	Id                int    `json:"id"`
+	Name              string `json:"name"`
+	Path              string `json:"path"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	ImportStatus      string `json:"import_status"`
+	ImportError       string `json:"import_error"`
+}
+
+func (s *ImportStatus) Finished() bool {
+	return s.ImportStatus == ImportStatusFinished || s.ImportStatus == ImportStatusFailed
+}
+
+type ImportProjectOpts struct {
+	// Name and path of the new project
+	Path string
+	Name string
+	// Id or path of the namespace to import into. Defaults to the current user's namespace.
+	Namespace string
+	Overwrite bool
+}
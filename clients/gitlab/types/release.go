@@ -0,0 +1,72 @@
+package types
+
+import "time"
+
+const (
+	ReleaseLinkTypeOther   = "other"
+	ReleaseLinkTypeRunbook = "runbook"
+	ReleaseLinkTypeImage   = "image"
+	ReleaseLinkTypePackage = "package"
+)
+
+type Release struct {
+	TagName     string        `json:"tag_name"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	CreatedAt   *time.Time    `json:"created_at"`
+	ReleasedAt  *time.Time    `json:"released_at"`
+	Author      *User         `json:"author"`
+	Commit      *Commit       `json:"commit"`
+	Assets      ReleaseAssets `json:"assets"`
+}
+
+type ReleaseAssets struct {
+	Count   int              `json:"count"`
+	Sources []*ReleaseSource `json:"sources"`
+	Links   []*ReleaseLink   `json:"links"`
+}
+
+type ReleaseSource struct {
+	Format string `json:"format"`
+	Url    string `json:"url"`
+}
+
+type ReleaseLink struct {
+	Id   int    `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	Url  string `json:"url,omitempty"`
+	// Path for a direct asset link, e.g. /binaries/linux-amd64
+	FilePath string `json:"filepath,omitempty"`
+	LinkType string `json:"link_type,omitempty"`
+	// Nil to keep it unchanged on update. Use Bool(false) to clear it.
+	External *bool `json:"external,omitempty"`
+}
+
+type CreateReleaseOpts struct {
+	TagName     string `json:"tag_name"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Commit SHA, branch or tag to create tag from if the tag does not exist
+	Ref        string     `json:"ref,omitempty"`
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+	Assets     *struct {
+		Links []*ReleaseLink `json:"links"`
+	} `json:"assets,omitempty"`
+}
+
+// AddLink adds asset link to be created with the release
+func (opts *CreateReleaseOpts) AddLink(link *ReleaseLink) *CreateReleaseOpts {
+	if nil == opts.Assets {
+		opts.Assets = &struct {
+			Links []*ReleaseLink `json:"links"`
+		}{}
+	}
+	opts.Assets.Links = append(opts.Assets.Links, link)
+	return opts
+}
+
+type UpdateReleaseOpts struct {
+	Name        string     `json:"name,omitempty"`
+	Description string     `json:"description,omitempty"`
+	ReleasedAt  *time.Time `json:"released_at,omitempty"`
+}
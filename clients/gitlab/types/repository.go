@@ -1,5 +1,10 @@
 package types
 
+import (
+	"fmt"
+	"net/url"
+)
+
 type RepoFile struct {
 	CommitBasicInfo
 	CommitContent
@@ -23,3 +28,49 @@ type RepoTreeObj struct {
 	Path string `json:"path"`
 	Mode string `json:"mode"`
 }
+
+const (
+	ArchiveFormatTarGz  = "tar.gz"
+	ArchiveFormatTarBz2 = "tar.bz2"
+	ArchiveFormatTar    = "tar"
+	ArchiveFormatZip    = "zip"
+)
+
+var validArchiveFormat = map[string]bool{
+	ArchiveFormatTarGz:  true,
+	ArchiveFormatTarBz2: true,
+	ArchiveFormatTar:    true,
+	ArchiveFormatZip:    true,
+}
+
+type ArchiveOpts struct {
+	// Commit SHA, branch or tag. Defaults to the default branch.
+	SHA string
+	// One of ArchiveFormat*. Defaults to tar.gz.
+	Format string
+	// Subdirectory of repository to archive
+	Path string
+}
+
+func (opts *ArchiveOpts) Check() error {
+	if nil != opts && "" != opts.Format && !validArchiveFormat[opts.Format] {
+		return fmt.Errorf("Invalid archive format '%s'", opts.Format)
+	}
+
+	return nil
+}
+
+func (opts *ArchiveOpts) ToQuery() url.Values {
+	query := make(url.Values)
+	if nil == opts {
+		return query
+	}
+
+	if "" != opts.SHA {
+		query.Set("sha", opts.SHA)
+	}
+	if "" != opts.Path {
+		query.Set("path", opts.Path)
+	}
+	return query
+}
@@ -0,0 +1,107 @@
+package types
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	SearchScopeProjects      = "projects"
+	SearchScopeIssues        = "issues"
+	SearchScopeMergeRequests = "merge_requests"
+	SearchScopeBlobs         = "blobs"
+	SearchScopeCommits       = "commits"
+)
+
+type SearchOpts struct {
+	Search string
+	// Search within the group or project if not empty. Only one of them can be set.
+	GroupId   string
+	ProjectId string
+	// Branch or tag to search blobs and commits, only used within project
+	Ref string
+	Pagination
+}
+
+func (opts *SearchOpts) ToQuery(scope string) (url.Values, error) {
+	if nil == opts || opts.Search == "" {
+		return nil, fmt.Errorf("Missing search")
+	}
+
+	if opts.GroupId != "" && opts.ProjectId != "" {
+		return nil, fmt.Errorf("Only one of group and project can be set")
+	}
+
+	if opts.Ref != "" && opts.ProjectId == "" {
+		return nil, fmt.Errorf("Ref can only be used within project")
+	}
+
+	if scope == SearchScopeProjects && opts.ProjectId != "" {
+		return nil, fmt.Errorf("Invalid scope '%s' within project", scope)
+	}
+
+	if err := opts.Pagination.check(); nil != err {
+		return nil, err
+	}
+
+	query := make(url.Values)
+	query.Set("scope", scope)
+	query.Set("search", opts.Search)
+	if "" != opts.Ref {
+		query.Set("ref", opts.Ref)
+	}
+	opts.Pagination.ToQuery(query)
+	return query, nil
+}
+
+type Issue struct {
+	Id          int        `json:"id"`
+	Iid         int        `json:"iid"`
+	ProjectId   int        `json:"project_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       string     `json:"state"`
+	Labels      []string   `json:"labels"`
+	Author      *User      `json:"author"`
+	Assignees   []*User    `json:"assignees"`
+	WebUrl      string     `json:"web_url"`
+	CreatedAt   *time.Time `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+	ClosedAt    *time.Time `json:"closed_at"`
+}
+
+type MergeRequest struct {
+	Id           int        `json:"id"`
+	Iid          int        `json:"iid"`
+	ProjectId    int        `json:"project_id"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	State        string     `json:"state"`
+	SourceBranch string     `json:"source_branch"`
+	TargetBranch string     `json:"target_branch"`
+	SHA          string     `json:"sha"`
+	Author       *User      `json:"author"`
+	WebUrl       string     `json:"web_url"`
+	CreatedAt    *time.Time `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at"`
+	MergedAt     *time.Time `json:"merged_at"`
+}
+
+// Blob is the matched part of a file
+type Blob struct {
+	Basename  string `json:"basename"`
+	Data      string `json:"data"`
+	Path      string `json:"path"`
+	Filename  string `json:"filename"`
+	Id        string `json:"id"`
+	Ref       string `json:"ref"`
+	StartLine int    `json:"startline"`
+	ProjectId int    `json:"project_id"`
+}
+
+// CommitResult is commit found by search
+type CommitResult struct {
+	Commit
+	ProjectId int `json:"project_id"`
+}
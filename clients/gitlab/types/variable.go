@@ -0,0 +1,22 @@
+package types
+
+const (
+	VariableTypeEnvVar = "env_var"
+	VariableTypeFile   = "file"
+)
+
+type Variable struct {
+	Key              string `json:"key"`
+	Value            string `json:"value"`
+	VariableType     string `json:"variable_type,omitempty"`
+	Protected        bool   `json:"protected"`
+	Masked           bool   `json:"masked"`
+	EnvironmentScope string `json:"environment_scope,omitempty"` // Only available for project variables
+}
+
+// PipelineVariable is variable passed to a single pipeline run
+type PipelineVariable struct {
+	Key          string `json:"key"`
+	Value        string `json:"value"`
+	VariableType string `json:"variable_type,omitempty"`
+}
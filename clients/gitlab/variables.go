@@ -0,0 +1,75 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/haborhuang/go-tools/clients/gitlab/types"
+)
+
+const (
+	projVariablesPathFmt = projectPathFmt + "/variables"
+	projVariablePathFmt  = projVariablesPathFmt + "/%s"
+)
+
+func projVariablesPath(projId string) string {
+	return fmt.Sprintf(projVariablesPathFmt, projId)
+}
+
+func projVariablePath(projId, key string) string {
+	return fmt.Sprintf(projVariablePathFmt, projId, key)
+}
+
+func envScopeQuery(envScope string) url.Values {
+	q := make(url.Values)
+	if envScope != "" {
+		q.Set("filter[environment_scope]", envScope)
+	}
+	return q
+}
+
+func (c *Client) ListProjectVariables(pid string) ([]*types.Variable, error) {
+	var res []*types.Variable
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(projVariablesPath(pid)),
+	).intoJson(&res)
+
+	return res, err
+}
+
+// GetProjectVariable gets the variable with specified key.
+// The envScope is required only if there are several variables with the same key.
+func (c *Client) GetProjectVariable(pid, key, envScope string) (*types.Variable, error) {
+	var v *types.Variable
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).RawSubPath(projVariablePath(pid, key)).Query(envScopeQuery(envScope)),
+	).intoJson(&v)
+
+	return v, err
+}
+
+func (c *Client) CreateProjectVariable(pid string, v *types.Variable) (*types.Variable, error) {
+	var nv *types.Variable
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPost).RawSubPath(projVariablesPath(pid)).JsonBody(v),
+	).intoJson(&nv)
+
+	return nv, err
+}
+
+func (c *Client) UpdateProjectVariable(pid string, v *types.Variable) (*types.Variable, error) {
+	var nv *types.Variable
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPut).RawSubPath(projVariablePath(pid, v.Key)).
+			Query(envScopeQuery(v.EnvironmentScope)).JsonBody(v),
+	).intoJson(&nv)
+
+	return nv, err
+}
+
+func (c *Client) DeleteProjectVariable(pid, key, envScope string) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodDelete).RawSubPath(projVariablePath(pid, key)).Query(envScopeQuery(envScope)),
+	).do()
+}
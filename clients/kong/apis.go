@@ -1,6 +1,7 @@
 package kong
 
 import (
+	"fmt"
 	"github.com/haborhuang/go-tools/clients/kong/types"
 	"path"
 	"net/http"
@@ -21,6 +22,67 @@ func (c *Client) GetAPI(nameOrId string) (*types.API, error) {
 	return res, err
 }
 
+// ListAPIs returns a page of APIs and offset of the next page
+func (c *Client) ListAPIs(opts *types.ListAPIsOpts) ([]*types.API, string, error) {
+	var res []*types.API
+	next, err := c.list(apisPath, opts.ToQuery(), &res)
+	return res, next, err
+}
+
+// APIIterator iterates APIs across pages, e.g.
+//   it := c.IterateAPIs(opts)
+//   for it.Next() {
+//       api := it.API()
+//   }
+//   if err := it.Err(); nil != err {
+//   }
+type APIIterator struct {
+	c    *Client
+	opts types.ListAPIsOpts
+	page []*types.API
+	api  *types.API
+	last bool
+	err  error
+}
+
+// IterateAPIs returns iterator of APIs filtered by opts. Size of opts is used as page size.
+func (c *Client) IterateAPIs(opts *types.ListAPIsOpts) *APIIterator {
+	it := &APIIterator{
+		c: c,
+	}
+	if nil != opts {
+		it.opts = *opts
+	}
+
+	return it
+}
+
+// Next moves to the next API and requests the next page if needed.
+// It returns false if there are no more APIs or error occurs.
+func (it *APIIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.last || nil != it.err {
+			return false
+		}
+
+		it.page, it.opts.Offset, it.err = it.c.ListAPIs(&it.opts)
+		it.last = it.opts.Offset == ""
+	}
+
+	it.api, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// API returns the current API
+func (it *APIIterator) API() *types.API {
+	return it.api
+}
+
+// Err returns error occurred during iteration
+func (it *APIIterator) Err() error {
+	return it.err
+}
+
 func (c *Client) AddAPI(req *types.API) (*types.API, error) {
 	var res *types.API
 	err := c.newResponse(
@@ -39,6 +101,69 @@ func (c *Client) UpdateAPI(nameOrId string, req *types.API) (*types.API, error)
 	return res, err
 }
 
+// Times to retry upsert if API is created or deleted by others concurrently
+const upsertRetries = 3
+
+// UpsertAPI creates API with name of req, or updates it if it exists, and returns whether it's changed.
+// No request is sent to update API if all the fields set in req are unchanged, see types.API.Diff.
+func (c *Client) UpsertAPI(req *types.API) (*types.API, bool, error) {
+	// Empty name would get the list of APIs instead
+	if nil == req || req.Name == "" {
+		return nil, false, fmt.Errorf("Name of API is required")
+	}
+
+	var err error
+	for i := 0; i < upsertRetries; i++ {
+		var current *types.API
+		current, err = c.GetAPI(req.Name)
+		if types.IsNotFoundErr(err) {
+			var res *types.API
+			res, err = c.AddAPI(req)
+			if types.IsConflictErr(err) {
+				// Created by others
+				continue
+			}
+			return res, nil == err, err
+		}
+		if nil != err {
+			return nil, false, err
+		}
+
+		if len(current.Diff(req)) == 0 {
+			return current, false, nil
+		}
+
+		var res *types.API
+		res, err = c.replaceAPI(current, req)
+		if types.IsNotFoundErr(err) {
+			// Deleted by others
+			continue
+		}
+		return res, nil == err, err
+	}
+
+	return nil, false, fmt.Errorf("Upsert API '%s' error after %d retries: %v", req.Name, upsertRetries, err)
+}
+
+// replaceAPI replaces API by PUT with fields set in req merged into current,
+// or updates it by PATCH if PUT isn't supported
+func (c *Client) replaceAPI(current, req *types.API) (*types.API, error) {
+	body, err := current.Merge(req)
+	if nil != err {
+		return nil, err
+	}
+
+	var res *types.API
+	err = c.newResponse(
+		c.newRequest().Method(http.MethodPut).SubPath(apisPath).JsonBody(body),
+	).intoJson(&res)
+	if kongErr, ok := err.(*types.Error); ok && kongErr.Status == http.StatusMethodNotAllowed {
+		return c.UpdateAPI(current.Id, req)
+	}
+
+	return res, err
+}
+
 func (c *Client) DeleteAPI(nameOrId string) error {
 	err := c.newResponse(
 		c.newRequest().Method(http.MethodDelete).SubPath(apiPath(nameOrId)),
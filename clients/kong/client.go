@@ -1,6 +1,7 @@
 package kong
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net/url"
 	"encoding/json"
@@ -12,11 +13,15 @@ import (
 )
 
 type Client struct {
-	url   *url.URL
+	url *url.URL
+	// Headers of all requests, e.g. credentials
+	headers    http.Header
+	tls        *tls.Config
+	httpClient *http.Client
 }
 
-func NewClientOrDie(domainUrl string) *Client {
-	c, err := NewClient(domainUrl)
+func NewClientOrDie(domainUrl string, opts ...Option) *Client {
+	c, err := NewClient(domainUrl, opts...)
 	if nil != err {
 		panic(fmt.Errorf("New client error: %v", err))
 	}
@@ -24,19 +29,38 @@ func NewClientOrDie(domainUrl string) *Client {
 	return c
 }
 
-func NewClient(domainUrl string) (*Client, error) {
+func NewClient(domainUrl string, opts ...Option) (*Client, error) {
 	u, err := url.Parse(domainUrl)
 	if nil != err {
 		return nil, fmt.Errorf("Parse url error: %v", err)
 	}
 
-	return &Client{
-		url:   u,
-	}, nil
+	c := &Client{
+		url:     u,
+		headers: make(http.Header),
+	}
+	for _, opt := range opts {
+		if err := opt(c); nil != err {
+			return nil, err
+		}
+	}
+	c.initHttpClient()
+
+	return c, nil
 }
 
 func (c *Client) newRequest() *clienttool.HttpRequest {
-	return clienttool.NewHttpReq(*c.url).SetHeader("Content-Type", "application/json")
+	req := clienttool.NewHttpReq(*c.url).SetHeader("Content-Type", "application/json")
+	for k, vs := range c.headers {
+		for _, v := range vs {
+			req.AddHeader(k, v)
+		}
+	}
+	if nil != c.httpClient {
+		req.Client(c.httpClient)
+	}
+
+	return req
 }
 
 type response struct {
@@ -87,3 +111,36 @@ func (r *response) intoJson(expected interface{}) error {
 
 	return nil
 }
+
+// list gets a page of entities into expected and returns offset of the next page,
+// which is empty if it's the last page
+func (c *Client) list(subPath string, query url.Values, expected interface{}) (string, error) {
+	var page struct {
+		Data   json.RawMessage `json:"data"`
+		Offset string          `json:"offset"`
+		Next   *string         `json:"next"`
+	}
+
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).SubPath(subPath).Query(query),
+	).intoJson(&page)
+	if nil != err {
+		return "", err
+	}
+
+	// Empty list may be encoded as {} by Kong
+	if len(page.Data) > 0 && page.Data[0] == '[' {
+		if err := json.Unmarshal(page.Data, expected); nil != err {
+			return "", fmt.Errorf("Decode response data error: %v", err)
+		}
+	}
+
+	if page.Offset == "" && nil != page.Next {
+		// Old versions only return URL of next page
+		if u, err := url.Parse(*page.Next); nil == err {
+			page.Offset = u.Query().Get("offset")
+		}
+	}
+
+	return page.Offset, nil
+}
@@ -0,0 +1,192 @@
+package kong
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/haborhuang/go-tools/clients/kong/types"
+)
+
+const consumersPath = "/consumers"
+
+// Sub paths of credentials of consumer
+const (
+	keyAuthPath   = "key-auth"
+	basicAuthPath = "basic-auth"
+	jwtPath       = "jwt"
+	hmacAuthPath  = "hmac-auth"
+	oauth2Path    = "oauth2"
+	aclsPath      = "acls"
+)
+
+func consumerPath(usernameOrId string) string {
+	return path.Join(consumersPath, usernameOrId)
+}
+
+func credentialsPath(consumer, kind string) string {
+	return path.Join(consumerPath(consumer), kind)
+}
+
+func credentialPath(consumer, kind, id string) string {
+	return path.Join(credentialsPath(consumer, kind), id)
+}
+
+func (c *Client) GetConsumer(usernameOrId string) (*types.Consumer, error) {
+	var res *types.Consumer
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).SubPath(consumerPath(usernameOrId)),
+	).intoJson(&res)
+
+	return res, err
+}
+
+// ListConsumers returns a page of consumers and offset of the next page
+func (c *Client) ListConsumers(opts *types.ListOpts) ([]*types.Consumer, string, error) {
+	var res []*types.Consumer
+	next, err := c.list(consumersPath, opts.ToQuery(), &res)
+	return res, next, err
+}
+
+func (c *Client) AddConsumer(req *types.Consumer) (*types.Consumer, error) {
+	var res *types.Consumer
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPost).SubPath(consumersPath).JsonBody(req),
+	).intoJson(&res)
+
+	return res, err
+}
+
+func (c *Client) UpdateConsumer(usernameOrId string, req *types.Consumer) (*types.Consumer, error) {
+	var res *types.Consumer
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPatch).SubPath(consumerPath(usernameOrId)).JsonBody(req),
+	).intoJson(&res)
+
+	return res, err
+}
+
+func (c *Client) DeleteConsumer(usernameOrId string) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodDelete).SubPath(consumerPath(usernameOrId)),
+	).do()
+}
+
+func (c *Client) addCredential(consumer, kind string, req, res interface{}) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodPost).SubPath(credentialsPath(consumer, kind)).JsonBody(req),
+	).intoJson(res)
+}
+
+func (c *Client) deleteCredential(consumer, kind, id string) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodDelete).SubPath(credentialPath(consumer, kind, id)),
+	).do()
+}
+
+func (c *Client) ListKeyAuths(consumer string, opts *types.ListOpts) ([]*types.KeyAuth, string, error) {
+	var res []*types.KeyAuth
+	next, err := c.list(credentialsPath(consumer, keyAuthPath), opts.ToQuery(), &res)
+	return res, next, err
+}
+
+// AddKeyAuth issues API key to consumer. The key is generated by Kong if req is nil or key is empty.
+func (c *Client) AddKeyAuth(consumer string, req *types.KeyAuth) (*types.KeyAuth, error) {
+	if nil == req {
+		req = &types.KeyAuth{}
+	}
+
+	var res *types.KeyAuth
+	err := c.addCredential(consumer, keyAuthPath, req, &res)
+	return res, err
+}
+
+func (c *Client) DeleteKeyAuth(consumer, keyOrId string) error {
+	return c.deleteCredential(consumer, keyAuthPath, keyOrId)
+}
+
+func (c *Client) ListBasicAuths(consumer string, opts *types.ListOpts) ([]*types.BasicAuth, string, error) {
+	var res []*types.BasicAuth
+	next, err := c.list(credentialsPath(consumer, basicAuthPath), opts.ToQuery(), &res)
+	return res, next, err
+}
+
+func (c *Client) AddBasicAuth(consumer string, req *types.BasicAuth) (*types.BasicAuth, error) {
+	var res *types.BasicAuth
+	err := c.addCredential(consumer, basicAuthPath, req, &res)
+	return res, err
+}
+
+func (c *Client) DeleteBasicAuth(consumer, usernameOrId string) error {
+	return c.deleteCredential(consumer, basicAuthPath, usernameOrId)
+}
+
+func (c *Client) ListJWTs(consumer string, opts *types.ListOpts) ([]*types.JWTCredential, string, error) {
+	var res []*types.JWTCredential
+	next, err := c.list(credentialsPath(consumer, jwtPath), opts.ToQuery(), &res)
+	return res, next, err
+}
+
+// AddJWT adds JWT credential to consumer. Key and secret are generated by Kong if req is nil or they are empty.
+func (c *Client) AddJWT(consumer string, req *types.JWTCredential) (*types.JWTCredential, error) {
+	if nil == req {
+		req = &types.JWTCredential{}
+	}
+
+	var res *types.JWTCredential
+	err := c.addCredential(consumer, jwtPath, req, &res)
+	return res, err
+}
+
+func (c *Client) DeleteJWT(consumer, keyOrId string) error {
+	return c.deleteCredential(consumer, jwtPath, keyOrId)
+}
+
+func (c *Client) ListHMACAuths(consumer string, opts *types.ListOpts) ([]*types.HMACAuth, string, error) {
+	var res []*types.HMACAuth
+	next, err := c.list(credentialsPath(consumer, hmacAuthPath), opts.ToQuery(), &res)
+	return res, next, err
+}
+
+func (c *Client) AddHMACAuth(consumer string, req *types.HMACAuth) (*types.HMACAuth, error) {
+	var res *types.HMACAuth
+	err := c.addCredential(consumer, hmacAuthPath, req, &res)
+	return res, err
+}
+
+func (c *Client) DeleteHMACAuth(consumer, usernameOrId string) error {
+	return c.deleteCredential(consumer, hmacAuthPath, usernameOrId)
+}
+
+func (c *Client) ListOAuth2s(consumer string, opts *types.ListOpts) ([]*types.OAuth2Credential, string, error) {
+	var res []*types.OAuth2Credential
+	next, err := c.list(credentialsPath(consumer, oauth2Path), opts.ToQuery(), &res)
+	return res, next, err
+}
+
+func (c *Client) AddOAuth2(consumer string, req *types.OAuth2Credential) (*types.OAuth2Credential, error) {
+	var res *types.OAuth2Credential
+	err := c.addCredential(consumer, oauth2Path, req, &res)
+	return res, err
+}
+
+func (c *Client) DeleteOAuth2(consumer, clientIdOrId string) error {
+	return c.deleteCredential(consumer, oauth2Path, clientIdOrId)
+}
+
+func (c *Client) ListACLs(consumer string, opts *types.ListOpts) ([]*types.ACL, string, error) {
+	var res []*types.ACL
+	next, err := c.list(credentialsPath(consumer, aclsPath), opts.ToQuery(), &res)
+	return res, next, err
+}
+
+// AddACL adds consumer into ACL group
+func (c *Client) AddACL(consumer, group string) (*types.ACL, error) {
+	var res *types.ACL
+	err := c.addCredential(consumer, aclsPath, &types.ACL{Group: group}, &res)
+	return res, err
+}
+
+// DeleteACL removes consumer from ACL group
+func (c *Client) DeleteACL(consumer, groupOrId string) error {
+	return c.deleteCredential(consumer, aclsPath, groupOrId)
+}
@@ -0,0 +1,274 @@
+package declarative
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/haborhuang/go-tools/clients/kong/types"
+)
+
+// Config is the desired state of Kong, encoded in YAML or JSON with the same field names as the Admin API.
+// Entities are identified by name, except that consumers are identified by username
+// and targets by address. Routes without name and consumers without username are identified by id.
+//
+// Absent sections are not managed, i.e. existing entities of them are kept,
+// while an empty section means all the existing entities of it should be deleted.
+type Config struct {
+	APIs      []*types.API      `json:"apis,omitempty"`
+	Services  []*Service        `json:"services,omitempty"`
+	Upstreams []*Upstream       `json:"upstreams,omitempty"`
+	Consumers []*types.Consumer `json:"consumers,omitempty"`
+	Plugins   []*Plugin         `json:"plugins,omitempty"`
+}
+
+// Service with routes attached to it. Routes are not managed if absent, or all deleted if empty.
+type Service struct {
+	types.Service
+	Routes []*types.Route `json:"routes"`
+}
+
+// Upstream with its targets. Targets are not managed if absent, or all deleted if empty.
+type Upstream struct {
+	types.Upstream
+	Targets []*types.Target `json:"targets"`
+}
+
+// Plugin refers to the entities it's applied to by name, e.g. a route and a consumer.
+// It's global if none of the entities is set.
+type Plugin struct {
+	Id        string                 `json:"id,omitempty"`
+	Name      string                 `json:"name"`
+	Api       string                 `json:"api,omitempty"`
+	Service   string                 `json:"service,omitempty"`
+	Route     string                 `json:"route,omitempty"`
+	Consumer  string                 `json:"consumer,omitempty"`
+	Config    map[string]interface{} `json:"config,omitempty"`
+	Enabled   *bool                  `json:"enabled,omitempty"`
+	Protocols []string               `json:"protocols,omitempty"`
+	Tags      []string               `json:"tags,omitempty"`
+}
+
+// entities returns the number of entities plugin is applied to
+func (p *Plugin) entities() int {
+	n := 0
+	for _, e := range []string{p.Api, p.Service, p.Route, p.Consumer} {
+		if e != "" {
+			n++
+		}
+	}
+
+	return n
+}
+
+// scope returns the entity plugin is applied to. Plugins applied to multiple entities,
+// e.g. route and consumer, are managed via the global endpoint, so nil is returned.
+func (p *Plugin) scope() *types.PluginScope {
+	if p.entities() > 1 {
+		return nil
+	}
+
+	return &types.PluginScope{
+		Api:      p.Api,
+		Service:  p.Service,
+		Route:    p.Route,
+		Consumer: p.Consumer,
+	}
+}
+
+// key identifies plugin by name and the entities it's applied to
+func (p *Plugin) key() string {
+	key := p.Name
+	if p.Api != "" {
+		key += "@api:" + p.Api
+	}
+	if p.Service != "" {
+		key += "@service:" + p.Service
+	}
+	if p.Route != "" {
+		key += "@route:" + p.Route
+	}
+	if p.Consumer != "" {
+		key += "@consumer:" + p.Consumer
+	}
+
+	return key
+}
+
+func (p *Plugin) toPlugin() *types.Plugin {
+	return &types.Plugin{
+		Name:      p.Name,
+		Config:    p.Config,
+		Enabled:   p.Enabled,
+		Protocols: p.Protocols,
+		Tags:      p.Tags,
+	}
+}
+
+// Load reads config from YAML or JSON file
+func Load(fpath string) (*Config, error) {
+	data, err := ioutil.ReadFile(fpath)
+	if nil != err {
+		return nil, fmt.Errorf("Read config file error: %v", err)
+	}
+
+	return Parse(data)
+}
+
+// Parse decodes and validates config encoded in YAML or JSON
+func Parse(data []byte) (*Config, error) {
+	// YAML is decoded into generic values and then into Config via JSON,
+	// so that JSON field names of types are used in both formats.
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); nil != err {
+		return nil, fmt.Errorf("Decode config error: %v", err)
+	}
+
+	data, err := json.Marshal(jsonValue(raw))
+	if nil != err {
+		return nil, fmt.Errorf("Encode config into JSON error: %v", err)
+	}
+
+	var conf Config
+	if err := json.Unmarshal(data, &conf); nil != err {
+		return nil, fmt.Errorf("Decode config error: %v", err)
+	}
+
+	if err := conf.Validate(); nil != err {
+		return nil, err
+	}
+
+	return &conf, nil
+}
+
+// jsonValue converts maps decoded by yaml into maps with string keys, which can be encoded into JSON
+func jsonValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, e := range val {
+			m[fmt.Sprint(k)] = jsonValue(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range val {
+			val[i] = jsonValue(e)
+		}
+	}
+
+	return v
+}
+
+// Validate checks that entities are identified uniquely.
+// The url shorthand of services is expanded into protocol, host, port and path.
+func (conf *Config) Validate() error {
+	names := make(map[string]bool)
+	checkName := func(kind, name string) error {
+		if name == "" {
+			return fmt.Errorf("Name or id of %s is required", kind)
+		}
+		if names[kind+"/"+name] {
+			return fmt.Errorf("Duplicate %s '%s'", kind, name)
+		}
+		names[kind+"/"+name] = true
+		return nil
+	}
+
+	for _, a := range conf.APIs {
+		if err := checkName(KindAPI, a.Name); nil != err {
+			return err
+		}
+	}
+
+	for _, s := range conf.Services {
+		if err := checkName(KindService, s.Name); nil != err {
+			return err
+		}
+		if err := expandServiceUrl(&s.Service); nil != err {
+			return err
+		}
+
+		for _, r := range s.Routes {
+			if err := checkName(KindRoute, routeKey(r)); nil != err {
+				return err
+			}
+			if nil != r.Service {
+				return fmt.Errorf("Service of route '%s' should not be set", routeKey(r))
+			}
+		}
+	}
+
+	for _, u := range conf.Upstreams {
+		if err := checkName(KindUpstream, u.Name); nil != err {
+			return err
+		}
+
+		for _, t := range u.Targets {
+			if err := checkName(KindTarget, u.Name+"/"+t.Target); nil != err {
+				return err
+			}
+		}
+	}
+
+	for _, c := range conf.Consumers {
+		if err := checkName(KindConsumer, consumerKey(c)); nil != err {
+			return err
+		}
+	}
+
+	for _, p := range conf.Plugins {
+		if p.Name == "" {
+			return fmt.Errorf("Name of plugin is required")
+		}
+		if err := p.checkScope(); nil != err {
+			return fmt.Errorf("Plugin '%s': %v", p.Name, err)
+		}
+		if err := checkName(KindPlugin, p.key()); nil != err {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkScope checks that plugin is applied to entities which can be combined.
+// Plugins of APIs can only be combined with consumers.
+func (p *Plugin) checkScope() error {
+	if p.Api != "" && (p.Service != "" || p.Route != "") {
+		return fmt.Errorf("Api can't be set along with service or route")
+	}
+
+	return nil
+}
+
+func expandServiceUrl(s *types.Service) error {
+	if s.Url == "" {
+		return nil
+	}
+
+	u, err := url.Parse(s.Url)
+	if nil != err {
+		return fmt.Errorf("Invalid url of service '%s': %v", s.Name, err)
+	}
+
+	s.Protocol = u.Scheme
+	s.Host = u.Hostname()
+	s.Path = u.Path
+	if port := u.Port(); port != "" {
+		s.Port, err = strconv.Atoi(port)
+		if nil != err {
+			return fmt.Errorf("Invalid port of service '%s': %v", s.Name, err)
+		}
+	} else if u.Scheme == "https" {
+		s.Port = 443
+	} else {
+		s.Port = 80
+	}
+	s.Url = ""
+
+	return nil
+}
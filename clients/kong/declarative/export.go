@@ -0,0 +1,166 @@
+package declarative
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/haborhuang/go-tools/clients/kong"
+	"github.com/haborhuang/go-tools/clients/kong/types"
+)
+
+// Export writes the current state of Kong into w in YAML, which can be restored by Sync
+func Export(c *kong.Client, w io.Writer) error {
+	conf, err := Fetch(c)
+	if nil != err {
+		return err
+	}
+
+	data, err := conf.MarshalStable()
+	if nil != err {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+// MarshalStable encodes config into YAML which doesn't change unless entities are changed,
+// i.e. generated fields are removed and entities and fields are sorted.
+// Ids are only kept for routes without name and consumers without username.
+// Empty sections are encoded, so they are restored as empty, while absent sections are kept absent.
+func (conf *Config) MarshalStable() ([]byte, error) {
+	data, err := json.Marshal(conf.stable())
+	if nil != err {
+		return nil, fmt.Errorf("Encode config error: %v", err)
+	}
+
+	var generic map[string]interface{}
+	if err := json.Unmarshal(data, &generic); nil != err {
+		return nil, fmt.Errorf("Decode config error: %v", err)
+	}
+	for section, present := range map[string]bool{
+		"apis":      nil != conf.APIs,
+		"services":  nil != conf.Services,
+		"upstreams": nil != conf.Upstreams,
+		"consumers": nil != conf.Consumers,
+		"plugins":   nil != conf.Plugins,
+	} {
+		if _, ok := generic[section]; !ok && present {
+			generic[section] = []interface{}{}
+		}
+	}
+
+	// Keys of maps are sorted by yaml
+	data, err = yaml.Marshal(dropNulls(generic))
+	if nil != err {
+		return nil, fmt.Errorf("Encode config into YAML error: %v", err)
+	}
+
+	return data, nil
+}
+
+// stable returns a sorted copy of config without generated fields
+func (conf *Config) stable() *Config {
+	res := &Config{}
+
+	for _, a := range conf.APIs {
+		ca := *a
+		ca.Id, ca.CreatedAt = "", 0
+		res.APIs = append(res.APIs, &ca)
+	}
+	sort.Slice(res.APIs, func(i, j int) bool {
+		return res.APIs[i].Name < res.APIs[j].Name
+	})
+
+	for _, s := range conf.Services {
+		cs := &Service{Service: s.Service}
+		cs.Id, cs.CreatedAt, cs.UpdatedAt = "", 0, 0
+		if nil != s.Routes {
+			cs.Routes = make([]*types.Route, 0, len(s.Routes))
+		}
+		for _, r := range s.Routes {
+			cr := *r
+			if cr.Name != "" {
+				cr.Id = ""
+			}
+			cr.CreatedAt, cr.UpdatedAt = 0, 0
+			cs.Routes = append(cs.Routes, &cr)
+		}
+		sort.Slice(cs.Routes, func(i, j int) bool {
+			return routeKey(cs.Routes[i]) < routeKey(cs.Routes[j])
+		})
+		res.Services = append(res.Services, cs)
+	}
+	sort.Slice(res.Services, func(i, j int) bool {
+		return res.Services[i].Name < res.Services[j].Name
+	})
+
+	for _, u := range conf.Upstreams {
+		cu := &Upstream{Upstream: u.Upstream}
+		cu.Id, cu.CreatedAt = "", 0
+		if nil != u.Targets {
+			cu.Targets = make([]*types.Target, 0, len(u.Targets))
+		}
+		for _, t := range u.Targets {
+			cu.Targets = append(cu.Targets, &types.Target{
+				Target: t.Target,
+				Weight: t.Weight,
+				Tags:   t.Tags,
+			})
+		}
+		sort.Slice(cu.Targets, func(i, j int) bool {
+			return cu.Targets[i].Target < cu.Targets[j].Target
+		})
+		res.Upstreams = append(res.Upstreams, cu)
+	}
+	sort.Slice(res.Upstreams, func(i, j int) bool {
+		return res.Upstreams[i].Name < res.Upstreams[j].Name
+	})
+
+	for _, c := range conf.Consumers {
+		cc := *c
+		if cc.Username != "" {
+			cc.Id = ""
+		}
+		cc.CreatedAt = 0
+		res.Consumers = append(res.Consumers, &cc)
+	}
+	sort.Slice(res.Consumers, func(i, j int) bool {
+		return consumerKey(res.Consumers[i]) < consumerKey(res.Consumers[j])
+	})
+
+	for _, p := range conf.Plugins {
+		cp := *p
+		cp.Id = ""
+		res.Plugins = append(res.Plugins, &cp)
+	}
+	sort.Slice(res.Plugins, func(i, j int) bool {
+		return res.Plugins[i].key() < res.Plugins[j].key()
+	})
+
+	return res
+}
+
+// dropNulls removes null fields from objects, e.g. unset fields of plugin config
+func dropNulls(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, e := range val {
+			if nil == e {
+				delete(val, k)
+				continue
+			}
+			val[k] = dropNulls(e)
+		}
+	case []interface{}:
+		for i, e := range val {
+			val[i] = dropNulls(e)
+		}
+	}
+
+	return v
+}
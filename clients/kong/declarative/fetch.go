@@ -0,0 +1,242 @@
+package declarative
+
+import (
+	"fmt"
+
+	"github.com/haborhuang/go-tools/clients/kong"
+	"github.com/haborhuang/go-tools/clients/kong/types"
+)
+
+const pageSize = 100
+
+// walk calls list with offset of each page until the last page
+func walk(list func(opts *types.ListOpts) (string, error)) error {
+	opts := &types.ListOpts{Size: pageSize}
+	for {
+		next, err := list(opts)
+		if nil != err {
+			return err
+		}
+		if next == "" {
+			return nil
+		}
+		opts.Offset = next
+	}
+}
+
+// walkOptional walks endpoints which are not supported by all versions of Kong,
+// e.g. APIs are removed since 1.0 while services and routes are added since 0.13.
+// It returns false if the endpoint is not found.
+func walkOptional(list func(opts *types.ListOpts) (string, error)) (bool, error) {
+	err := walk(list)
+	if types.IsNotFoundErr(err) {
+		return false, nil
+	}
+
+	return nil == err, err
+}
+
+// Fetch reads the current state of Kong.
+// Sections which are not supported by Kong are left absent, so they are not managed when restored.
+func Fetch(c *kong.Client) (*Config, error) {
+	conf := &Config{
+		APIs:      []*types.API{},
+		Services:  []*Service{},
+		Upstreams: []*Upstream{},
+		Consumers: []*types.Consumer{},
+		Plugins:   []*Plugin{},
+	}
+
+	found, err := walkOptional(func(opts *types.ListOpts) (string, error) {
+		page, next, err := c.ListAPIs(&types.ListAPIsOpts{ListOpts: *opts})
+		conf.APIs = append(conf.APIs, page...)
+		return next, err
+	})
+	if nil != err {
+		return nil, fmt.Errorf("List APIs error: %v", err)
+	}
+	if !found {
+		conf.APIs = nil
+	}
+
+	services := make(map[string]*Service)
+	found, err = walkOptional(func(opts *types.ListOpts) (string, error) {
+		page, next, err := c.ListServices(opts)
+		for _, s := range page {
+			svc := &Service{Service: *s, Routes: []*types.Route{}}
+			services[s.Id] = svc
+			conf.Services = append(conf.Services, svc)
+		}
+		return next, err
+	})
+	if nil != err {
+		return nil, fmt.Errorf("List services error: %v", err)
+	}
+	if !found {
+		conf.Services = nil
+	}
+
+	var routes []*types.Route
+	found, err = walkOptional(func(opts *types.ListOpts) (string, error) {
+		page, next, err := c.ListRoutes(opts)
+		routes = append(routes, page...)
+		return next, err
+	})
+	if nil != err {
+		return nil, fmt.Errorf("List routes error: %v", err)
+	}
+	if !found {
+		for _, s := range conf.Services {
+			s.Routes = nil
+		}
+	}
+	for _, r := range routes {
+		if nil == r.Service {
+			continue
+		}
+		if svc, ok := services[r.Service.Id]; ok {
+			cr := *r
+			cr.Service = nil
+			svc.Routes = append(svc.Routes, &cr)
+		}
+	}
+
+	found, err = walkOptional(func(opts *types.ListOpts) (string, error) {
+		page, next, err := c.ListUpstreams(opts)
+		for _, u := range page {
+			conf.Upstreams = append(conf.Upstreams, &Upstream{Upstream: *u, Targets: []*types.Target{}})
+		}
+		return next, err
+	})
+	if nil != err {
+		return nil, fmt.Errorf("List upstreams error: %v", err)
+	}
+	if !found {
+		conf.Upstreams = nil
+	}
+	for _, u := range conf.Upstreams {
+		err = walk(func(opts *types.ListOpts) (string, error) {
+			page, next, err := c.ListTargets(u.Id, opts)
+			for _, t := range page {
+				ct := *t
+				ct.Upstream = nil
+				u.Targets = append(u.Targets, &ct)
+			}
+			return next, err
+		})
+		if nil != err {
+			return nil, fmt.Errorf("List targets of upstream '%s' error: %v", u.Name, err)
+		}
+	}
+
+	err = walk(func(opts *types.ListOpts) (string, error) {
+		page, next, err := c.ListConsumers(opts)
+		conf.Consumers = append(conf.Consumers, page...)
+		return next, err
+	})
+	if nil != err {
+		return nil, fmt.Errorf("List consumers error: %v", err)
+	}
+
+	names := newEntityNames(conf, routes)
+	err = walk(func(opts *types.ListOpts) (string, error) {
+		page, next, err := c.ListPlugins(nil, opts)
+		for _, p := range page {
+			conf.Plugins = append(conf.Plugins, names.plugin(p))
+		}
+		return next, err
+	})
+	if nil != err {
+		return nil, fmt.Errorf("List plugins error: %v", err)
+	}
+
+	return conf, nil
+}
+
+// entityNames maps ids of entities to their names, which are used to refer to entities in Config
+type entityNames struct {
+	apis      map[string]string
+	services  map[string]string
+	routes    map[string]string
+	consumers map[string]string
+}
+
+func newEntityNames(conf *Config, routes []*types.Route) *entityNames {
+	names := &entityNames{
+		apis:      make(map[string]string),
+		services:  make(map[string]string),
+		routes:    make(map[string]string),
+		consumers: make(map[string]string),
+	}
+
+	for _, a := range conf.APIs {
+		names.apis[a.Id] = a.Name
+	}
+	for _, s := range conf.Services {
+		names.services[s.Id] = s.Name
+	}
+	for _, r := range routes {
+		names.routes[r.Id] = routeKey(r)
+	}
+	for _, c := range conf.Consumers {
+		names.consumers[c.Id] = consumerKey(c)
+	}
+
+	return names
+}
+
+func (names *entityNames) name(m map[string]string, id string) string {
+	if n, ok := m[id]; ok && n != "" {
+		return n
+	}
+
+	return id
+}
+
+func (names *entityNames) plugin(p *types.Plugin) *Plugin {
+	res := &Plugin{
+		Id:        p.Id,
+		Name:      p.Name,
+		Config:    p.Config,
+		Enabled:   p.Enabled,
+		Protocols: p.Protocols,
+		Tags:      p.Tags,
+	}
+
+	if p.ApiId != "" {
+		res.Api = names.name(names.apis, p.ApiId)
+	}
+	if nil != p.Service {
+		res.Service = names.name(names.services, p.Service.Id)
+	}
+	if nil != p.Route {
+		res.Route = names.name(names.routes, p.Route.Id)
+	}
+	consumerId := p.ConsumerId
+	if nil != p.Consumer {
+		consumerId = p.Consumer.Id
+	}
+	if consumerId != "" {
+		res.Consumer = names.name(names.consumers, consumerId)
+	}
+
+	return res
+}
+
+// routeKey returns name of route, or id if it's unnamed
+func routeKey(r *types.Route) string {
+	if r.Name != "" {
+		return r.Name
+	}
+
+	return r.Id
+}
+
+// consumerKey returns username of consumer, or id if username is not set
+func consumerKey(c *types.Consumer) string {
+	if c.Username != "" {
+		return c.Username
+	}
+
+	return c.Id
+}
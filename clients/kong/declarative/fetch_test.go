@@ -0,0 +1,74 @@
+package declarative
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/haborhuang/go-tools/clients/kong"
+)
+
+// Responses of Kong 2.x, which doesn't support APIs. Upstreams are not found as if they're disabled.
+var fetchResponses = map[string]string{
+	"/services":  `{"data":[{"id":"s1","name":"users","protocol":"http","host":"users.internal","port":80}],"next":null}`,
+	"/routes":    `{"data":[{"id":"r1","name":"users-api","paths":["/users"],"service":{"id":"s1"}}],"next":null}`,
+	"/consumers": `{"data":[{"id":"c1","username":"alice"}],"next":null}`,
+	"/plugins":   `{"data":[{"id":"p1","name":"rate-limiting","route":{"id":"r1"},"consumer":{"id":"c1"},"config":{"minute":10}}],"next":null}`,
+}
+
+func TestFetch(t *testing.T) {
+	responses := make(map[string]string)
+	for k, v := range fetchResponses {
+		responses[k] = v
+	}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Not found"}`))
+			return
+		}
+		w.Write([]byte(res))
+	}))
+	defer s.Close()
+	c := kong.NewClientOrDie(s.URL)
+
+	conf, err := Fetch(c)
+	if nil != err {
+		t.Fatalf("Fetch error: %v", err)
+	}
+	if nil != conf.APIs || nil != conf.Upstreams {
+		t.Fatalf("Expect unsupported sections absent, got %+v and %+v", conf.APIs, conf.Upstreams)
+	}
+	if len(conf.Services) != 1 || len(conf.Services[0].Routes) != 1 {
+		t.Fatalf("Unexpected services %+v", conf.Services)
+	}
+	if len(conf.Plugins) != 1 || conf.Plugins[0].Route != "users-api" || conf.Plugins[0].Consumer != "alice" {
+		t.Fatalf("Unexpected plugins %+v", conf.Plugins)
+	}
+
+	data, err := conf.MarshalStable()
+	if nil != err {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if strings.Contains(string(data), "apis:") || strings.Contains(string(data), "upstreams:") {
+		t.Fatalf("Unsupported sections are exported:\n%s", data)
+	}
+	restored, err := Parse(data)
+	if nil != err {
+		t.Fatalf("Parse error: %v\n%s", err, data)
+	}
+	if nil != restored.APIs || nil != restored.Upstreams {
+		t.Fatalf("Unsupported sections are restored:\n%s", data)
+	}
+	if plan := diff(conf, restored); !plan.Empty() {
+		t.Fatalf("Restored config differs:\n%s", plan)
+	}
+
+	// Consumers are supported by all versions
+	delete(responses, "/consumers")
+	if _, err := Fetch(c); nil == err {
+		t.Errorf("Expect error of consumers not found")
+	}
+}
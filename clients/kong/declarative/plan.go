@@ -0,0 +1,674 @@
+package declarative
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/haborhuang/go-tools/clients/kong"
+	"github.com/haborhuang/go-tools/clients/kong/types"
+)
+
+// Kinds of entities
+const (
+	KindAPI      = "api"
+	KindService  = "service"
+	KindRoute    = "route"
+	KindUpstream = "upstream"
+	KindTarget   = "target"
+	KindConsumer = "consumer"
+	KindPlugin   = "plugin"
+)
+
+// Operations of changes
+const (
+	OpCreate = "create"
+	OpUpdate = "update"
+	OpDelete = "delete"
+)
+
+var opSymbols = map[string]string{
+	OpCreate: "+",
+	OpUpdate: "~",
+	OpDelete: "-",
+}
+
+// Change of an entity
+type Change struct {
+	Op   string
+	Kind string
+	// Identity of entity, e.g. name of service
+	Key string
+
+	apply func(c *kong.Client) error
+}
+
+func (ch *Change) String() string {
+	return fmt.Sprintf("%s %s %s", opSymbols[ch.Op], ch.Kind, ch.Key)
+}
+
+// Plan is a list of changes in the order they are applied.
+// Creations and updates are applied in dependency order at first, e.g. services before their routes,
+// and then deletions in the reverse order.
+type Plan struct {
+	Changes []*Change
+
+	deletes []*Change
+}
+
+func (p *Plan) add(op, kind, key string, apply func(c *kong.Client) error) {
+	ch := &Change{
+		Op:    op,
+		Kind:  kind,
+		Key:   key,
+		apply: apply,
+	}
+
+	if op == OpDelete {
+		p.deletes = append(p.deletes, ch)
+		return
+	}
+	p.Changes = append(p.Changes, ch)
+}
+
+// Empty returns true if there is nothing to change
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+func (p *Plan) String() string {
+	counts := make(map[string]int)
+	buf := bytes.NewBuffer(nil)
+	for _, ch := range p.Changes {
+		counts[ch.Op]++
+		fmt.Fprintln(buf, ch)
+	}
+	fmt.Fprintf(buf, "Plan: %d to create, %d to update, %d to delete.\n",
+		counts[OpCreate], counts[OpUpdate], counts[OpDelete])
+
+	return buf.String()
+}
+
+// Apply applies changes in order and stops at the first error
+func (p *Plan) Apply(c *kong.Client) error {
+	for _, ch := range p.Changes {
+		if err := ch.apply(c); nil != err {
+			return fmt.Errorf("%s %s '%s' error: %v", ch.Op, ch.Kind, ch.Key, err)
+		}
+	}
+
+	return nil
+}
+
+// Sync makes Kong in the desired state. The plan is written into w before being applied.
+// Nothing is changed if dryRun is true.
+func Sync(c *kong.Client, desired *Config, w io.Writer, dryRun bool) (*Plan, error) {
+	plan, err := Diff(c, desired)
+	if nil != err {
+		return nil, err
+	}
+
+	if nil != w {
+		fmt.Fprint(w, plan)
+	}
+	if dryRun {
+		return plan, nil
+	}
+
+	return plan, plan.Apply(c)
+}
+
+// Diff fetches the current state of Kong and returns changes required to reach the desired state
+func Diff(c *kong.Client, desired *Config) (*Plan, error) {
+	if err := desired.Validate(); nil != err {
+		return nil, err
+	}
+
+	current, err := Fetch(c)
+	if nil != err {
+		return nil, err
+	}
+
+	return diff(current, desired), nil
+}
+
+func diff(current, desired *Config) *Plan {
+	plan := &Plan{}
+
+	// In dependency order
+	diffUpstreams(plan, current.Upstreams, desired.Upstreams)
+	diffServices(plan, current.Services, desired.Services)
+	diffAPIs(plan, current.APIs, desired.APIs)
+	diffConsumers(plan, current.Consumers, desired.Consumers)
+	diffPlugins(plan, current.Plugins, desired.Plugins)
+
+	for i := len(plan.deletes) - 1; i >= 0; i-- {
+		plan.Changes = append(plan.Changes, plan.deletes[i])
+	}
+	plan.deletes = nil
+
+	return plan
+}
+
+func diffAPIs(plan *Plan, current, desired []*types.API) {
+	if nil == desired {
+		return
+	}
+
+	existing := make(map[string]*types.API)
+	for _, a := range current {
+		existing[a.Name] = a
+	}
+
+	for _, a := range desired {
+		old, ok := existing[a.Name]
+		delete(existing, a.Name)
+		switch {
+		case !ok:
+			plan.add(OpCreate, KindAPI, a.Name, addAPI(a))
+		case !matches(old, a):
+			plan.add(OpUpdate, KindAPI, a.Name, updateAPI(old.Id, a))
+		}
+	}
+
+	for _, a := range current {
+		if _, ok := existing[a.Name]; ok {
+			plan.add(OpDelete, KindAPI, a.Name, deleteAPI(a.Id))
+		}
+	}
+}
+
+func addAPI(a *types.API) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		_, err := c.AddAPI(a)
+		return err
+	}
+}
+
+func updateAPI(id string, a *types.API) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		_, err := c.UpdateAPI(id, a)
+		return err
+	}
+}
+
+func deleteAPI(id string) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		return c.DeleteAPI(id)
+	}
+}
+
+func diffServices(plan *Plan, current, desired []*Service) {
+	if nil == desired {
+		return
+	}
+
+	existing := make(map[string]*Service)
+	routes := make(map[string]*types.Route)
+	for _, s := range current {
+		existing[s.Name] = s
+		for _, r := range s.Routes {
+			routes[routeKey(r)] = r
+		}
+	}
+
+	// Routes may be moved between services, so they are kept if desired by any service
+	wanted := make(map[string]bool)
+	for _, s := range desired {
+		for _, r := range s.Routes {
+			wanted[routeKey(r)] = true
+		}
+	}
+
+	for _, s := range desired {
+		old, ok := existing[s.Name]
+		delete(existing, s.Name)
+		switch {
+		case !ok:
+			plan.add(OpCreate, KindService, s.Name, addService(&s.Service))
+		case !matches(&old.Service, &s.Service):
+			plan.add(OpUpdate, KindService, s.Name, updateService(old.Id, &s.Service))
+		}
+
+		var oldRoutes []*types.Route
+		if ok {
+			oldRoutes = old.Routes
+		}
+		diffRoutes(plan, s.Name, oldRoutes, s.Routes, routes, wanted)
+	}
+
+	for _, s := range current {
+		if _, ok := existing[s.Name]; ok {
+			plan.add(OpDelete, KindService, s.Name, deleteService(s.Id))
+			// Deletions are applied in reverse order, so routes are deleted before their service
+			for _, r := range s.Routes {
+				if !wanted[routeKey(r)] {
+					plan.add(OpDelete, KindRoute, routeKey(r), deleteRoute(r.Id))
+				}
+			}
+		}
+	}
+}
+
+// diffRoutes compares routes of the service. Existing routes are looked up in routes of all services,
+// and routes which are wanted by any service are not deleted.
+func diffRoutes(plan *Plan, service string, current, desired []*types.Route, all map[string]*types.Route, wanted map[string]bool) {
+	if nil == desired {
+		return
+	}
+
+	ofService := make(map[string]bool)
+	for _, r := range current {
+		ofService[routeKey(r)] = true
+	}
+
+	for _, r := range desired {
+		key := routeKey(r)
+		old, ok := all[key]
+		switch {
+		case !ok:
+			plan.add(OpCreate, KindRoute, key, addRoute(service, r))
+		case !ofService[key]:
+			plan.add(OpUpdate, KindRoute, key, moveRoute(old.Id, service, r))
+		case !matches(old, r):
+			plan.add(OpUpdate, KindRoute, key, updateRoute(old.Id, r))
+		}
+	}
+
+	for _, r := range current {
+		if !wanted[routeKey(r)] {
+			plan.add(OpDelete, KindRoute, routeKey(r), deleteRoute(r.Id))
+		}
+	}
+}
+
+func addService(s *types.Service) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		_, err := c.AddService(s)
+		return err
+	}
+}
+
+func updateService(id string, s *types.Service) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		_, err := c.UpdateService(id, s)
+		return err
+	}
+}
+
+func deleteService(id string) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		return c.DeleteService(id)
+	}
+}
+
+func addRoute(service string, r *types.Route) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		_, err := c.AddServiceRoute(service, r)
+		return err
+	}
+}
+
+func updateRoute(id string, r *types.Route) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		_, err := c.UpdateRoute(id, r)
+		return err
+	}
+}
+
+// moveRoute attaches route to another service, which may be created by the plan
+func moveRoute(id, service string, r *types.Route) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		svc, err := c.GetService(service)
+		if nil != err {
+			return err
+		}
+
+		req := *r
+		req.Service = &types.Ref{Id: svc.Id}
+		_, err = c.UpdateRoute(id, &req)
+		return err
+	}
+}
+
+func deleteRoute(id string) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		return c.DeleteRoute(id)
+	}
+}
+
+func diffUpstreams(plan *Plan, current, desired []*Upstream) {
+	if nil == desired {
+		return
+	}
+
+	existing := make(map[string]*Upstream)
+	for _, u := range current {
+		existing[u.Name] = u
+	}
+
+	for _, u := range desired {
+		old, ok := existing[u.Name]
+		delete(existing, u.Name)
+		switch {
+		case !ok:
+			plan.add(OpCreate, KindUpstream, u.Name, addUpstream(&u.Upstream))
+		case !matches(&old.Upstream, &u.Upstream):
+			plan.add(OpUpdate, KindUpstream, u.Name, updateUpstream(old.Id, &u.Upstream))
+		}
+
+		var oldTargets []*types.Target
+		if ok {
+			oldTargets = old.Targets
+		}
+		diffTargets(plan, u.Name, oldTargets, u.Targets)
+	}
+
+	// Targets are deleted along with upstreams
+	for _, u := range current {
+		if _, ok := existing[u.Name]; ok {
+			plan.add(OpDelete, KindUpstream, u.Name, deleteUpstream(u.Id))
+		}
+	}
+}
+
+func diffTargets(plan *Plan, upstream string, current, desired []*types.Target) {
+	if nil == desired {
+		return
+	}
+
+	existing := make(map[string]*types.Target)
+	for _, t := range current {
+		existing[t.Target] = t
+	}
+
+	for _, t := range desired {
+		key := upstream + "/" + t.Target
+		old, ok := existing[t.Target]
+		delete(existing, t.Target)
+		switch {
+		case !ok:
+			plan.add(OpCreate, KindTarget, key, addTarget(upstream, t))
+		case !matches(old, t):
+			// Adding target again overrides the existing one
+			plan.add(OpUpdate, KindTarget, key, addTarget(upstream, t))
+		}
+	}
+
+	for _, t := range current {
+		if _, ok := existing[t.Target]; ok {
+			plan.add(OpDelete, KindTarget, upstream+"/"+t.Target, deleteTarget(upstream, t.Id))
+		}
+	}
+}
+
+func addUpstream(u *types.Upstream) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		_, err := c.AddUpstream(u)
+		return err
+	}
+}
+
+func updateUpstream(id string, u *types.Upstream) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		_, err := c.UpdateUpstream(id, u)
+		return err
+	}
+}
+
+func deleteUpstream(id string) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		return c.DeleteUpstream(id)
+	}
+}
+
+func addTarget(upstream string, t *types.Target) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		_, err := c.AddTarget(upstream, t)
+		return err
+	}
+}
+
+func deleteTarget(upstream, id string) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		return c.DeleteTarget(upstream, id)
+	}
+}
+
+func diffConsumers(plan *Plan, current, desired []*types.Consumer) {
+	if nil == desired {
+		return
+	}
+
+	existing := make(map[string]*types.Consumer)
+	for _, c := range current {
+		existing[consumerKey(c)] = c
+	}
+
+	for _, c := range desired {
+		key := consumerKey(c)
+		old, ok := existing[key]
+		delete(existing, key)
+		switch {
+		case !ok:
+			plan.add(OpCreate, KindConsumer, key, addConsumer(c))
+		case !matches(old, c):
+			plan.add(OpUpdate, KindConsumer, key, updateConsumer(old.Id, c))
+		}
+	}
+
+	for _, c := range current {
+		if _, ok := existing[consumerKey(c)]; ok {
+			plan.add(OpDelete, KindConsumer, consumerKey(c), deleteConsumer(c.Id))
+		}
+	}
+}
+
+func addConsumer(consumer *types.Consumer) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		_, err := c.AddConsumer(consumer)
+		return err
+	}
+}
+
+func updateConsumer(id string, consumer *types.Consumer) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		_, err := c.UpdateConsumer(id, consumer)
+		return err
+	}
+}
+
+func deleteConsumer(id string) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		return c.DeleteConsumer(id)
+	}
+}
+
+func diffPlugins(plan *Plan, current, desired []*Plugin) {
+	if nil == desired {
+		return
+	}
+
+	existing := make(map[string]*Plugin)
+	for _, p := range current {
+		existing[p.key()] = p
+	}
+
+	for _, p := range desired {
+		key := p.key()
+		old, ok := existing[key]
+		delete(existing, key)
+		switch {
+		case !ok:
+			plan.add(OpCreate, KindPlugin, key, addPlugin(p))
+		case !matches(old.toPlugin(), p.toPlugin()):
+			plan.add(OpUpdate, KindPlugin, key, updatePlugin(old.Id, p))
+		}
+	}
+
+	for _, p := range current {
+		if _, ok := existing[p.key()]; ok {
+			plan.add(OpDelete, KindPlugin, p.key(), deletePlugin(p))
+		}
+	}
+}
+
+func addPlugin(p *Plugin) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		req, err := pluginRequest(c, p)
+		if nil != err {
+			return err
+		}
+
+		_, err = c.AddPlugin(p.scope(), req)
+		return err
+	}
+}
+
+func updatePlugin(id string, p *Plugin) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		req, err := pluginRequest(c, p)
+		if nil != err {
+			return err
+		}
+
+		_, err = c.UpdatePlugin(p.scope(), id, req)
+		return err
+	}
+}
+
+// pluginRequest returns the plugin to be sent to Kong. Plugins applied to multiple entities
+// are sent to the global endpoint, so they refer to the entities by ids, which may be created by the plan.
+func pluginRequest(c *kong.Client, p *Plugin) (*types.Plugin, error) {
+	req := p.toPlugin()
+	if p.entities() <= 1 {
+		return req, nil
+	}
+
+	if p.Api != "" {
+		a, err := c.GetAPI(p.Api)
+		if nil != err {
+			return nil, err
+		}
+		req.ApiId = a.Id
+	}
+	if p.Service != "" {
+		s, err := c.GetService(p.Service)
+		if nil != err {
+			return nil, err
+		}
+		req.Service = &types.Ref{Id: s.Id}
+	}
+	if p.Route != "" {
+		r, err := c.GetRoute(p.Route)
+		if nil != err {
+			return nil, err
+		}
+		req.Route = &types.Ref{Id: r.Id}
+	}
+	if p.Consumer != "" {
+		consumer, err := c.GetConsumer(p.Consumer)
+		if nil != err {
+			return nil, err
+		}
+		// APIs and consumers are referred by the same form in old versions
+		if p.Api != "" {
+			req.ConsumerId = consumer.Id
+		} else {
+			req.Consumer = &types.Ref{Id: consumer.Id}
+		}
+	}
+
+	return req, nil
+}
+
+func deletePlugin(p *Plugin) func(c *kong.Client) error {
+	return func(c *kong.Client) error {
+		return c.DeletePlugin(p.scope(), p.Id)
+	}
+}
+
+// Fields which are generated by Kong and not compared
+var generatedFields = []string{"id", "created_at", "updated_at"}
+
+// matches returns true if all fields set in desired entity equal to the current ones.
+// Fields which are not set are left as they are by Kong, e.g. default values.
+func matches(current, desired interface{}) bool {
+	cur, err := genericValue(current)
+	if nil != err {
+		return false
+	}
+	des, err := genericValue(desired)
+	if nil != err {
+		return false
+	}
+
+	if m, ok := des.(map[string]interface{}); ok {
+		for _, f := range generatedFields {
+			delete(m, f)
+		}
+	}
+
+	return contains(cur, des)
+}
+
+// genericValue converts entity into generic value decoded from JSON
+func genericValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if nil != err {
+		return nil, err
+	}
+
+	var res interface{}
+	err = json.Unmarshal(data, &res)
+	return res, err
+}
+
+func contains(current, desired interface{}) bool {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		c, ok := current.(map[string]interface{})
+		if !ok {
+			return len(d) == 0 && isEmpty(current)
+		}
+		for k, v := range d {
+			if !contains(c[k], v) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		if len(d) == 0 {
+			return isEmpty(current)
+		}
+		c, ok := current.([]interface{})
+		if !ok || len(c) != len(d) {
+			return false
+		}
+		for i := range d {
+			if !contains(c[i], d[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return reflect.DeepEqual(current, desired)
+}
+
+// isEmpty returns true for null, empty array and empty object.
+// Kong may encode empty array as empty object.
+func isEmpty(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case []interface{}:
+		return len(val) == 0
+	case map[string]interface{}:
+		return len(val) == 0
+	}
+
+	return false
+}
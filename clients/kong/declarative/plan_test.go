@@ -0,0 +1,132 @@
+package declarative
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/haborhuang/go-tools/clients/kong/types"
+)
+
+const desiredYaml = `
+services:
+- name: users
+  url: http://users.internal:8080/v1
+  routes:
+  - name: users-api
+    paths: [/users]
+upstreams:
+- name: users.internal
+  targets:
+  - target: 10.0.0.1:8080
+plugins:
+- name: cors
+  service: users
+  config:
+    origins: ["*"]
+`
+
+func TestDiff(t *testing.T) {
+	desired, err := Parse([]byte(desiredYaml))
+	if nil != err {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if s := desired.Services[0]; s.Host != "users.internal" || s.Port != 8080 || s.Path != "/v1" || s.Url != "" {
+		t.Fatalf("Url of service is not expanded: %+v", s.Service)
+	}
+
+	current := &Config{
+		Services: []*Service{{
+			Service: types.Service{Id: "s1", Name: "users", Protocol: "http", Host: "users.internal", Port: 80, Path: "/v1"},
+			Routes: []*types.Route{
+				{Id: "r1", Name: "users-api", Paths: []string{"/users"}},
+				{Id: "r2", Name: "legacy", Paths: []string{"/old"}},
+			},
+		}},
+		Upstreams: []*Upstream{{
+			Upstream: types.Upstream{Id: "u1", Name: "users.internal"},
+			Targets:  []*types.Target{{Id: "t1", Target: "10.0.0.1:8080", Weight: types.Int(100)}},
+		}},
+		Plugins: []*Plugin{
+			{Id: "p1", Name: "cors", Service: "users", Config: map[string]interface{}{"origins": []interface{}{"*"}, "max_age": nil}},
+			{Id: "p2", Name: "rate-limiting"},
+		},
+	}
+
+	// Deletions are applied in reverse dependency order
+	expected := []string{
+		"~ service users",
+		"- plugin rate-limiting",
+		"- route legacy",
+	}
+
+	plan := diff(current, desired)
+	var got []string
+	for _, ch := range plan.Changes {
+		got = append(got, ch.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("Unexpected plan:\n%s", plan)
+	}
+}
+
+func TestPluginScopes(t *testing.T) {
+	conf := &Config{Plugins: []*Plugin{
+		{Name: "rate-limiting", Route: "users-api"},
+		{Name: "rate-limiting", Route: "users-api", Consumer: "alice"},
+		{Name: "rate-limiting", Api: "legacy", Consumer: "alice"},
+	}}
+	if err := conf.Validate(); nil != err {
+		t.Fatalf("Validate error: %v", err)
+	}
+	if scope := conf.Plugins[1].scope(); nil != scope {
+		t.Errorf("Expect global endpoint for plugin of route and consumer, got %+v", scope)
+	}
+
+	conf.Plugins = append(conf.Plugins, &Plugin{Name: "cors", Api: "legacy", Service: "users"})
+	if err := conf.Validate(); nil == err {
+		t.Errorf("Expect error of plugin of api and service")
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	current := &Config{
+		APIs: []*types.API{{Id: "a1", Name: "legacy", UpstreamUrl: "http://legacy.internal"}},
+		Services: []*Service{{
+			Service: types.Service{Id: "s1", Name: "users", Protocol: "http", Host: "users.internal", Port: 80},
+			Routes: []*types.Route{
+				{Id: "r2", Paths: []string{"/old"}},
+				{Id: "r1", Name: "users-api", Paths: []string{"/users"}},
+			},
+		}},
+		// Empty targets are exported, so that targets added later are deleted on restore
+		Upstreams: []*Upstream{{Upstream: types.Upstream{Id: "u1", Name: "orders"}, Targets: []*types.Target{}}},
+		Consumers: []*types.Consumer{{Id: "c1", Username: "alice"}},
+		Plugins: []*Plugin{
+			{Id: "p1", Name: "cors", Route: "r2", Config: map[string]interface{}{"origins": []interface{}{"*"}, "max_age": nil}},
+		},
+	}
+
+	data, err := current.MarshalStable()
+	if nil != err {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if strings.Contains(string(data), "a1") || strings.Contains(string(data), "max_age") {
+		t.Fatalf("Generated or null fields are exported:\n%s", data)
+	}
+
+	restored, err := Parse(data)
+	if nil != err {
+		t.Fatalf("Parse error: %v\n%s", err, data)
+	}
+	if len(restored.Upstreams) != 1 || nil == restored.Upstreams[0].Targets {
+		t.Fatalf("Empty targets are not restored:\n%s", data)
+	}
+	if plan := diff(current, restored); !plan.Empty() {
+		t.Fatalf("Restored config differs:\n%s", plan)
+	}
+
+	again, _ := restored.MarshalStable()
+	if string(again) != string(data) {
+		t.Fatalf("Export is not stable:\n%s\n%s", data, again)
+	}
+}
@@ -0,0 +1,274 @@
+package kongtest
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/haborhuang/go-tools/clients/kong/types"
+)
+
+const (
+	defaultRetries = 5
+	defaultTimeout = 60000
+	defaultSize    = 100
+)
+
+// AddAPI adds API directly, e.g. to prepare data for tests. Id and created_at are generated if empty.
+func (s *Server) AddAPI(api *types.API) *types.API {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	a := api.Copy()
+	if a.Id == "" {
+		a.Id = newId()
+	}
+	if a.CreatedAt == 0 {
+		a.CreatedAt = time.Now().UnixNano() / int64(time.Millisecond)
+	}
+	s.apis = append(s.apis, a)
+
+	return a.Copy()
+}
+
+// APIs returns copies of all APIs in order of creation
+func (s *Server) APIs() []*types.API {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	res := make([]*types.API, 0, len(s.apis))
+	for _, a := range s.apis {
+		res = append(res, a.Copy())
+	}
+
+	return res
+}
+
+func (s *Server) findAPI(nameOrId string) (int, *types.API) {
+	for i, a := range s.apis {
+		if a.Id == nameOrId || a.Name == nameOrId {
+			return i, a
+		}
+	}
+
+	return -1, nil
+}
+
+func (s *Server) serveAPIs(w http.ResponseWriter, r *http.Request, segs []string) {
+	if len(segs) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			s.listAPIs(w, r)
+		case http.MethodPost:
+			s.createAPI(w, r)
+		case http.MethodPut:
+			s.putAPI(w, r)
+		default:
+			writeMethodNotAllowed(w)
+		}
+		return
+	}
+	if len(segs) > 2 {
+		writeNotFound(w)
+		return
+	}
+
+	i, a := s.findAPI(segs[1])
+	if nil == a {
+		writeNotFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJson(w, http.StatusOK, a)
+	case http.MethodPatch:
+		// Decode into a copy so that the stored API is unchanged if the request is rejected
+		updated := a.Copy()
+		fields, err := decodeBody(r, updated)
+		if nil != err {
+			writeErr(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		updated.Id, updated.CreatedAt = a.Id, a.CreatedAt
+		if fields["name"] && s.conflicts(updated) {
+			writeErr(w, http.StatusConflict, fmt.Sprintf("already exists with value '%s'", updated.Name))
+			return
+		}
+		if err := checkAPI(updated); nil != err {
+			writeErr(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.apis[i] = updated
+		writeJson(w, http.StatusOK, updated)
+	case http.MethodDelete:
+		s.apis = append(s.apis[:i], s.apis[i+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func (s *Server) listAPIs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var matched []*types.API
+	for _, a := range s.apis {
+		if name := q.Get("name"); name != "" && a.Name != name {
+			continue
+		}
+		if u := q.Get("upstream_url"); u != "" && a.UpstreamUrl != u {
+			continue
+		}
+		matched = append(matched, a)
+	}
+
+	size, _ := strconv.Atoi(q.Get("size"))
+	if size <= 0 {
+		size = defaultSize
+	}
+	start := 0
+	if offset := q.Get("offset"); offset != "" {
+		data, err := base64.StdEncoding.DecodeString(offset)
+		if nil == err {
+			start, err = strconv.Atoi(string(data))
+		}
+		if nil != err || start < 0 || start > len(matched) {
+			writeErr(w, http.StatusBadRequest, "offset is not a valid offset for this page")
+			return
+		}
+	}
+	end := start + size
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	res := map[string]interface{}{
+		"total": len(matched),
+		// Kong encodes empty array as empty object
+		"data": map[string]interface{}{},
+	}
+	if end > start {
+		res["data"] = matched[start:end]
+	}
+	if end < len(matched) {
+		offset := base64.StdEncoding.EncodeToString([]byte(strconv.Itoa(end)))
+		next := *r.URL
+		nq := next.Query()
+		nq.Set("offset", offset)
+		next.RawQuery = nq.Encode()
+		res["offset"] = offset
+		res["next"] = s.URL + next.RequestURI()
+	}
+
+	writeJson(w, http.StatusOK, res)
+}
+
+func (s *Server) createAPI(w http.ResponseWriter, r *http.Request) {
+	var a types.API
+	fields, err := decodeBody(r, &a)
+	if nil != err {
+		writeErr(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.create(w, &a, fields)
+}
+
+func (s *Server) create(w http.ResponseWriter, a *types.API, fields map[string]bool) {
+	if err := checkAPI(a); nil != err {
+		writeErr(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if s.conflicts(a) {
+		writeErr(w, http.StatusConflict, fmt.Sprintf("already exists with value '%s'", a.Name))
+		return
+	}
+
+	setDefaults(a, fields)
+	if a.Id == "" {
+		a.Id = newId()
+	}
+	a.CreatedAt = time.Now().UnixNano() / int64(time.Millisecond)
+	s.apis = append(s.apis, a)
+	writeJson(w, http.StatusCreated, a)
+}
+
+// putAPI replaces API specified by id in body, or creates it if id is not set
+func (s *Server) putAPI(w http.ResponseWriter, r *http.Request) {
+	var a types.API
+	fields, err := decodeBody(r, &a)
+	if nil != err {
+		writeErr(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if a.Id == "" {
+		s.create(w, &a, fields)
+		return
+	}
+
+	i, old := s.findAPI(a.Id)
+	if nil == old || old.Id != a.Id {
+		writeNotFound(w)
+		return
+	}
+	if err := checkAPI(&a); nil != err {
+		writeErr(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if s.conflicts(&a) {
+		writeErr(w, http.StatusConflict, fmt.Sprintf("already exists with value '%s'", a.Name))
+		return
+	}
+
+	setDefaults(&a, fields)
+	a.CreatedAt = old.CreatedAt
+	s.apis[i] = &a
+	writeJson(w, http.StatusOK, &a)
+}
+
+// conflicts returns true if name of a is used by another API
+func (s *Server) conflicts(a *types.API) bool {
+	for _, e := range s.apis {
+		if e.Name == a.Name && e.Id != a.Id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func checkAPI(a *types.API) error {
+	switch {
+	case a.Name == "":
+		return fmt.Errorf("name is required")
+	case a.UpstreamUrl == "":
+		return fmt.Errorf("upstream_url is required")
+	case len(a.Hosts) == 0 && len(a.Uris) == 0 && len(a.Methods) == 0:
+		return fmt.Errorf("at least one of 'hosts', 'uris' or 'methods' must be specified")
+	}
+
+	return nil
+}
+
+// setDefaults sets default values of fields which are not in request body
+func setDefaults(a *types.API, fields map[string]bool) {
+	if !fields["strip_uri"] {
+		a.StripUri = true
+	}
+	if a.Retries == 0 {
+		a.Retries = defaultRetries
+	}
+	if a.UpstreamConnectTimeout == 0 {
+		a.UpstreamConnectTimeout = defaultTimeout
+	}
+	if a.UpstreamSendTimeout == 0 {
+		a.UpstreamSendTimeout = defaultTimeout
+	}
+	if a.UpstreamReadTimeout == 0 {
+		a.UpstreamReadTimeout = defaultTimeout
+	}
+}
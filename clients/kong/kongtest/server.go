@@ -0,0 +1,105 @@
+// Package kongtest provides an in-memory Kong Admin API server for testing code using the kong client.
+package kongtest
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/haborhuang/go-tools/clients/kong"
+	"github.com/haborhuang/go-tools/clients/kong/types"
+)
+
+// Server is a fake Kong Admin API which keeps entities in memory.
+// Only the /apis endpoints are implemented.
+type Server struct {
+	URL string
+
+	srv  *httptest.Server
+	lock sync.Mutex
+
+	// APIs in order of creation
+	apis []*types.API
+}
+
+// NewServer starts a fake Admin API. It should be closed by Close when finished.
+func NewServer() *Server {
+	s := &Server{}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// NewClient returns a client accessing the server
+func (s *Server) NewClient(opts ...kong.Option) *kong.Client {
+	return kong.NewClientOrDie(s.URL, opts...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	segs := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch segs[0] {
+	case "apis":
+		s.serveAPIs(w, r, segs)
+	default:
+		writeNotFound(w)
+	}
+}
+
+// newId returns a random UUID as Kong does
+func newId() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func writeJson(w http.ResponseWriter, status int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(obj)
+}
+
+// writeErr writes error in the same form as Kong, which is parsed by types.ParseErr
+func writeErr(w http.ResponseWriter, status int, msg string) {
+	writeJson(w, status, types.Error{Message: msg})
+}
+
+func writeNotFound(w http.ResponseWriter) {
+	writeErr(w, http.StatusNotFound, "Not found")
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter) {
+	writeErr(w, http.StatusMethodNotAllowed, "Method not allowed")
+}
+
+// decodeBody decodes JSON body into obj and returns the names of fields in body
+func decodeBody(r *http.Request, obj interface{}) (map[string]bool, error) {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); nil != err {
+		return nil, fmt.Errorf("Cannot parse JSON body")
+	}
+
+	fields := make(map[string]bool)
+	for k := range raw {
+		fields[k] = true
+	}
+
+	data, _ := json.Marshal(raw)
+	if err := json.Unmarshal(data, obj); nil != err {
+		return nil, fmt.Errorf("Invalid body: %v", err)
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,82 @@
+package kongtest
+
+import (
+	"testing"
+
+	"github.com/haborhuang/go-tools/clients/kong/types"
+)
+
+func TestAPIs(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	for _, name := range []string{"a", "b", "c"} {
+		_, err := c.AddAPI(&types.API{Name: name, Uris: []string{"/" + name}, UpstreamUrl: "http://" + name + ".internal"})
+		if nil != err {
+			t.Fatalf("Add API error: %v", err)
+		}
+	}
+	if _, err := c.AddAPI(&types.API{Name: "a", Uris: []string{"/x"}, UpstreamUrl: "http://x"}); !types.IsConflictErr(err) {
+		t.Fatalf("Expect conflict error but got %v", err)
+	}
+	if _, err := c.GetAPI("d"); !types.IsNotFoundErr(err) {
+		t.Fatalf("Expect not found error but got %v", err)
+	}
+
+	it := c.IterateAPIs(&types.ListAPIsOpts{ListOpts: types.ListOpts{Size: 2}})
+	var names []string
+	for it.Next() {
+		names = append(names, it.API().Name)
+	}
+	if nil != it.Err() || len(names) != 3 || names[2] != "c" {
+		t.Fatalf("Unexpected APIs %v, error: %v", names, it.Err())
+	}
+
+	apis, next, err := c.ListAPIs(&types.ListAPIsOpts{Name: "d"})
+	if nil != err || len(apis) != 0 || next != "" {
+		t.Fatalf("Unexpected APIs %v, next '%s', error: %v", apis, next, err)
+	}
+
+	api, changed, err := c.UpsertAPI(&types.API{Name: "b", Uris: []string{"/b"}, UpstreamUrl: "http://b.internal"})
+	if nil != err || changed {
+		t.Fatalf("Expect no change, changed: %v, error: %v", changed, err)
+	}
+	api, changed, err = c.UpsertAPI(&types.API{Name: "b", Uris: []string{"/b"}, UpstreamUrl: "http://b2.internal"})
+	if nil != err || !changed || api.UpstreamUrl != "http://b2.internal" || api.Retries != 5 {
+		t.Fatalf("Unexpected API %+v, changed: %v, error: %v", api, changed, err)
+	}
+	_, changed, err = c.UpsertAPI(&types.API{Name: "d", Uris: []string{"/d"}, UpstreamUrl: "http://d.internal"})
+	if nil != err || !changed {
+		t.Fatalf("Expect API created, changed: %v, error: %v", changed, err)
+	}
+	if _, _, err := c.UpsertAPI(&types.API{Uris: []string{"/e"}, UpstreamUrl: "http://e.internal"}); nil == err {
+		t.Fatalf("Expect error of API without name")
+	}
+
+	if err := c.DeleteAPI("a"); nil != err {
+		t.Fatalf("Delete API error: %v", err)
+	}
+	if apis := s.APIs(); len(apis) != 3 || apis[0].Name != "b" {
+		t.Fatalf("Unexpected APIs %+v", apis)
+	}
+}
+
+func TestAPICopies(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	added := s.AddAPI(&types.API{Name: "a", Uris: []string{"/a"}, UpstreamUrl: "http://a.internal"})
+	added.Uris[0] = "/changed"
+	s.APIs()[0].Uris[0] = "/changed"
+
+	// Rejected for missing name and upstream_url
+	if _, err := c.UpdateAPI("a", &types.API{Uris: []string{"/x"}}); nil == err {
+		t.Fatalf("Expect error of invalid API")
+	}
+
+	if apis := s.APIs(); len(apis) != 1 || apis[0].Uris[0] != "/a" {
+		t.Fatalf("Stored API is changed to %+v", apis[0])
+	}
+}
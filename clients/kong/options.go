@@ -0,0 +1,108 @@
+package kong
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// Option configures Client, e.g. authentication of Admin API
+type Option func(c *Client) error
+
+// WithHeader sets header of all requests
+func WithHeader(k, v string) Option {
+	return func(c *Client) error {
+		c.headers.Set(k, v)
+		return nil
+	}
+}
+
+// WithAPIKey sets key of key-auth plugin protecting Admin API, which is sent in header "apikey"
+func WithAPIKey(key string) Option {
+	return WithHeader("apikey", key)
+}
+
+// WithBasicAuth sets credential of basic-auth plugin protecting Admin API
+func WithBasicAuth(username, password string) Option {
+	return func(c *Client) error {
+		req := http.Request{Header: make(http.Header)}
+		req.SetBasicAuth(username, password)
+		c.headers.Set("Authorization", req.Header.Get("Authorization"))
+		return nil
+	}
+}
+
+// WithRBACToken sets RBAC token of Kong Enterprise
+func WithRBACToken(token string) Option {
+	return WithHeader("Kong-Admin-Token", token)
+}
+
+// WithClientCert sets PEM encoded client certificate and key files for mutual TLS
+func WithClientCert(certFile, keyFile string) Option {
+	return func(c *Client) error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if nil != err {
+			return fmt.Errorf("Load client certificate error: %v", err)
+		}
+
+		c.tlsConfig().Certificates = append(c.tlsConfig().Certificates, cert)
+		return nil
+	}
+}
+
+// WithCABundle sets PEM encoded CA certificates file to verify Admin API, instead of CAs of system
+func WithCABundle(caFile string) Option {
+	return func(c *Client) error {
+		data, err := ioutil.ReadFile(caFile)
+		if nil != err {
+			return fmt.Errorf("Read CA bundle error: %v", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return fmt.Errorf("No certificate found in CA bundle '%s'", caFile)
+		}
+
+		c.tlsConfig().RootCAs = pool
+		return nil
+	}
+}
+
+// WithInsecureSkipVerify disables verification of certificate of Admin API
+func WithInsecureSkipVerify() Option {
+	return func(c *Client) error {
+		c.tlsConfig().InsecureSkipVerify = true
+		return nil
+	}
+}
+
+// WithHttpClient sets HTTP client to send requests. TLS options are ignored if it's set.
+func WithHttpClient(hc *http.Client) Option {
+	return func(c *Client) error {
+		c.httpClient = hc
+		return nil
+	}
+}
+
+func (c *Client) tlsConfig() *tls.Config {
+	if nil == c.tls {
+		c.tls = &tls.Config{}
+	}
+
+	return c.tls
+}
+
+// initHttpClient creates HTTP client with TLS config if any
+func (c *Client) initHttpClient() {
+	if nil != c.httpClient || nil == c.tls {
+		return
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = c.tls
+	c.httpClient = &http.Client{
+		Transport: transport,
+	}
+}
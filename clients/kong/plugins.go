@@ -0,0 +1,146 @@
+package kong
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/haborhuang/go-tools/clients/kong/types"
+)
+
+const pluginsPath = "/plugins"
+
+// scopedPluginsPath returns path of plugins applied to the entity specified by scope
+func scopedPluginsPath(scope *types.PluginScope) (string, error) {
+	if nil == scope {
+		return pluginsPath, nil
+	}
+
+	var paths []string
+	if scope.Api != "" {
+		paths = append(paths, apiPath(scope.Api))
+	}
+	if scope.Service != "" {
+		paths = append(paths, servicePath(scope.Service))
+	}
+	if scope.Route != "" {
+		paths = append(paths, routePath(scope.Route))
+	}
+	if scope.Consumer != "" {
+		paths = append(paths, consumerPath(scope.Consumer))
+	}
+
+	switch len(paths) {
+	case 0:
+		return pluginsPath, nil
+	case 1:
+		return path.Join(paths[0], pluginsPath), nil
+	}
+
+	return "", fmt.Errorf("Only one entity can be specified in plugin scope")
+}
+
+func scopedPluginPath(scope *types.PluginScope, id string) (string, error) {
+	p, err := scopedPluginsPath(scope)
+	if nil != err {
+		return "", err
+	}
+
+	return path.Join(p, id), nil
+}
+
+// ListPlugins returns a page of plugins in scope and offset of the next page.
+// All plugins are listed if scope is nil.
+func (c *Client) ListPlugins(scope *types.PluginScope, opts *types.ListOpts) ([]*types.Plugin, string, error) {
+	subPath, err := scopedPluginsPath(scope)
+	if nil != err {
+		return nil, "", err
+	}
+
+	var res []*types.Plugin
+	next, err := c.list(subPath, opts.ToQuery(), &res)
+	return res, next, err
+}
+
+func (c *Client) GetPlugin(scope *types.PluginScope, id string) (*types.Plugin, error) {
+	subPath, err := scopedPluginPath(scope, id)
+	if nil != err {
+		return nil, err
+	}
+
+	var res *types.Plugin
+	err = c.newResponse(
+		c.newRequest().Method(http.MethodGet).SubPath(subPath),
+	).intoJson(&res)
+
+	return res, err
+}
+
+// AddPlugin applies plugin to the entity specified by scope, or globally if scope is nil
+func (c *Client) AddPlugin(scope *types.PluginScope, req *types.Plugin) (*types.Plugin, error) {
+	subPath, err := scopedPluginsPath(scope)
+	if nil != err {
+		return nil, err
+	}
+
+	var res *types.Plugin
+	err = c.newResponse(
+		c.newRequest().Method(http.MethodPost).SubPath(subPath).JsonBody(req),
+	).intoJson(&res)
+
+	return res, err
+}
+
+func (c *Client) UpdatePlugin(scope *types.PluginScope, id string, req *types.Plugin) (*types.Plugin, error) {
+	subPath, err := scopedPluginPath(scope, id)
+	if nil != err {
+		return nil, err
+	}
+
+	var res *types.Plugin
+	err = c.newResponse(
+		c.newRequest().Method(http.MethodPatch).SubPath(subPath).JsonBody(req),
+	).intoJson(&res)
+
+	return res, err
+}
+
+func (c *Client) DeletePlugin(scope *types.PluginScope, id string) error {
+	subPath, err := scopedPluginPath(scope, id)
+	if nil != err {
+		return err
+	}
+
+	return c.newResponse(
+		c.newRequest().Method(http.MethodDelete).SubPath(subPath),
+	).do()
+}
+
+// ListEnabledPlugins returns names of plugins enabled on the node
+func (c *Client) ListEnabledPlugins() ([]string, error) {
+	var res struct {
+		EnabledPlugins []string `json:"enabled_plugins"`
+	}
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).SubPath(path.Join(pluginsPath, "enabled")),
+	).intoJson(&res)
+
+	return res.EnabledPlugins, err
+}
+
+// GetPluginSchema returns schema of config of the plugin
+func (c *Client) GetPluginSchema(name string) (json.RawMessage, error) {
+	var res json.RawMessage
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).SubPath(path.Join(pluginsPath, "schema", name)),
+	).intoJson(&res)
+	if types.IsNotFoundErr(err) {
+		// Newer versions serve schemas under /schemas
+		err = c.newResponse(
+			c.newRequest().Method(http.MethodGet).SubPath(path.Join("/schemas", pluginsPath, name)),
+		).intoJson(&res)
+	}
+
+	return res, err
+}
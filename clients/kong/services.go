@@ -0,0 +1,122 @@
+package kong
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/haborhuang/go-tools/clients/kong/types"
+)
+
+const (
+	servicesPath = "/services"
+	routesPath   = "/routes"
+)
+
+func servicePath(nameOrId string) string {
+	return path.Join(servicesPath, nameOrId)
+}
+
+func serviceRoutesPath(serviceNameOrId string) string {
+	return path.Join(servicePath(serviceNameOrId), routesPath)
+}
+
+func routePath(nameOrId string) string {
+	return path.Join(routesPath, nameOrId)
+}
+
+func (c *Client) GetService(nameOrId string) (*types.Service, error) {
+	var res *types.Service
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).SubPath(servicePath(nameOrId)),
+	).intoJson(&res)
+
+	return res, err
+}
+
+// ListServices returns a page of services and offset of the next page
+func (c *Client) ListServices(opts *types.ListOpts) ([]*types.Service, string, error) {
+	var res []*types.Service
+	next, err := c.list(servicesPath, opts.ToQuery(), &res)
+	return res, next, err
+}
+
+func (c *Client) AddService(req *types.Service) (*types.Service, error) {
+	var res *types.Service
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPost).SubPath(servicesPath).JsonBody(req),
+	).intoJson(&res)
+
+	return res, err
+}
+
+func (c *Client) UpdateService(nameOrId string, req *types.Service) (*types.Service, error) {
+	var res *types.Service
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPatch).SubPath(servicePath(nameOrId)).JsonBody(req),
+	).intoJson(&res)
+
+	return res, err
+}
+
+func (c *Client) DeleteService(nameOrId string) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodDelete).SubPath(servicePath(nameOrId)),
+	).do()
+}
+
+func (c *Client) GetRoute(nameOrId string) (*types.Route, error) {
+	var res *types.Route
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).SubPath(routePath(nameOrId)),
+	).intoJson(&res)
+
+	return res, err
+}
+
+// ListRoutes returns a page of routes and offset of the next page
+func (c *Client) ListRoutes(opts *types.ListOpts) ([]*types.Route, string, error) {
+	var res []*types.Route
+	next, err := c.list(routesPath, opts.ToQuery(), &res)
+	return res, next, err
+}
+
+// ListServiceRoutes returns a page of routes of the service and offset of the next page
+func (c *Client) ListServiceRoutes(serviceNameOrId string, opts *types.ListOpts) ([]*types.Route, string, error) {
+	var res []*types.Route
+	next, err := c.list(serviceRoutesPath(serviceNameOrId), opts.ToQuery(), &res)
+	return res, next, err
+}
+
+// AddRoute adds route. Route.Service should be set to attach the route to a service.
+func (c *Client) AddRoute(req *types.Route) (*types.Route, error) {
+	var res *types.Route
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPost).SubPath(routesPath).JsonBody(req),
+	).intoJson(&res)
+
+	return res, err
+}
+
+func (c *Client) AddServiceRoute(serviceNameOrId string, req *types.Route) (*types.Route, error) {
+	var res *types.Route
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPost).SubPath(serviceRoutesPath(serviceNameOrId)).JsonBody(req),
+	).intoJson(&res)
+
+	return res, err
+}
+
+func (c *Client) UpdateRoute(nameOrId string, req *types.Route) (*types.Route, error) {
+	var res *types.Route
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPatch).SubPath(routePath(nameOrId)).JsonBody(req),
+	).intoJson(&res)
+
+	return res, err
+}
+
+func (c *Client) DeleteRoute(nameOrId string) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodDelete).SubPath(routePath(nameOrId)),
+	).do()
+}
@@ -0,0 +1,49 @@
+package kong
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/haborhuang/go-tools/clients/kong/types"
+)
+
+// GetNodeInfo returns information of the node serving the request
+func (c *Client) GetNodeInfo() (*types.NodeInfo, error) {
+	var res *types.NodeInfo
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet),
+	).intoJson(&res)
+
+	return res, err
+}
+
+// GetNodeStatus returns connection counters and database reachability of the node serving the request
+func (c *Client) GetNodeStatus() (*types.NodeStatus, error) {
+	var res *types.NodeStatus
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).SubPath("/status"),
+	).intoJson(&res)
+
+	return res, err
+}
+
+// ListTargetsHealth returns a page of targets of upstream with health seen by the node, and offset of the next page
+func (c *Client) ListTargetsHealth(upstreamNameOrId string, opts *types.ListOpts) ([]*types.TargetHealth, string, error) {
+	var res []*types.TargetHealth
+	next, err := c.list(path.Join(upstreamPath(upstreamNameOrId), "health"), opts.ToQuery(), &res)
+	return res, next, err
+}
+
+// SetTargetHealthy marks target as healthy in the cluster, until the result of health checks changes it
+func (c *Client) SetTargetHealthy(upstreamNameOrId, targetOrId string) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodPost).SubPath(path.Join(targetPath(upstreamNameOrId, targetOrId), "healthy")),
+	).do()
+}
+
+// SetTargetUnhealthy marks target as unhealthy in the cluster, until the result of health checks changes it
+func (c *Client) SetTargetUnhealthy(upstreamNameOrId, targetOrId string) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodPost).SubPath(path.Join(targetPath(upstreamNameOrId, targetOrId), "unhealthy")),
+	).do()
+}
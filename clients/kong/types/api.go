@@ -1,5 +1,13 @@
 package types
 
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+)
+
 type API struct {
 	Id                     string   `json:"id,omitempty"`
 	CreatedAt              int64    `json:"created_at,omitempty"`
@@ -17,3 +25,86 @@ type API struct {
 	HttpsOnly              bool     `json:"https_only"`
 	HttpIfTerminated       bool     `json:"http_if_terminated"`
 }
+
+// Diff returns JSON names of fields which are set in desired and differ from a.
+// Fields omitted from JSON of desired are unset, e.g. zero retries, and id and created_at are ignored.
+func (a *API) Diff(desired *API) []string {
+	current, des := apiFields(a), apiFields(desired)
+
+	var fields []string
+	for k, v := range des {
+		if k == "id" || k == "created_at" {
+			continue
+		}
+		if !reflect.DeepEqual(current[k], v) {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+
+	return fields
+}
+
+// Merge returns copy of a with fields set in desired, see Diff
+func (a *API) Merge(desired *API) (*API, error) {
+	data, err := json.Marshal(desired)
+	if nil != err {
+		return nil, fmt.Errorf("Encode API error: %v", err)
+	}
+
+	// Decoding reuses slices of the target, so decode into a copy to keep a unchanged
+	merged := a.Copy()
+	if err := json.Unmarshal(data, merged); nil != err {
+		return nil, fmt.Errorf("Decode API error: %v", err)
+	}
+	merged.Id, merged.CreatedAt = a.Id, a.CreatedAt
+
+	return merged, nil
+}
+
+// Copy returns a deep copy of a
+func (a *API) Copy() *API {
+	na := *a
+	na.Hosts = copyStrings(a.Hosts)
+	na.Uris = copyStrings(a.Uris)
+	na.Methods = copyStrings(a.Methods)
+	return &na
+}
+
+func copyStrings(s []string) []string {
+	if nil == s {
+		return nil
+	}
+
+	return append(make([]string, 0, len(s)), s...)
+}
+
+func apiFields(a *API) map[string]interface{} {
+	fields := make(map[string]interface{})
+	if data, err := json.Marshal(a); nil == err {
+		json.Unmarshal(data, &fields)
+	}
+
+	return fields
+}
+
+type ListAPIsOpts struct {
+	ListOpts
+	Name        string
+	UpstreamUrl string
+}
+
+func (opts *ListAPIsOpts) ToQuery() url.Values {
+	if nil == opts {
+		return make(url.Values)
+	}
+
+	query := opts.ListOpts.ToQuery()
+	if "" != opts.Name {
+		query.Set("name", opts.Name)
+	}
+	if "" != opts.UpstreamUrl {
+		query.Set("upstream_url", opts.UpstreamUrl)
+	}
+	return query
+}
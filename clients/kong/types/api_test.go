@@ -0,0 +1,70 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAPIDiff(t *testing.T) {
+	current := &API{
+		Id:          "1",
+		Name:        "app",
+		Uris:        []string{"/app"},
+		UpstreamUrl: "http://app",
+		Retries:     5,
+	}
+
+	for _, c := range []struct {
+		desired *API
+		fields  []string
+	}{
+		{&API{Name: "app", Uris: []string{"/app"}, UpstreamUrl: "http://app"}, nil},
+		// Unset retries is not a difference
+		{&API{Id: "2", Name: "app", UpstreamUrl: "http://app"}, nil},
+		{&API{Name: "app", Uris: []string{"/v2"}, UpstreamUrl: "http://app", Retries: 3}, []string{"retries", "uris"}},
+		{&API{Name: "app", UpstreamUrl: "http://app", PreserveHost: true}, []string{"preserve_host"}},
+	} {
+		if fields := current.Diff(c.desired); !reflect.DeepEqual(fields, c.fields) {
+			t.Errorf("Expect diff %v of %+v, got %v", c.fields, c.desired, fields)
+		}
+	}
+}
+
+func TestAPIMerge(t *testing.T) {
+	current := &API{
+		Id:          "1",
+		CreatedAt:   100,
+		Name:        "app",
+		Hosts:       []string{"a.com", "b.com"},
+		Uris:        []string{"/app"},
+		UpstreamUrl: "http://app",
+		Retries:     5,
+	}
+
+	merged, err := current.Merge(&API{Id: "2", Name: "app", Hosts: []string{"c.com"}, UpstreamUrl: "http://v2"})
+	if nil != err {
+		t.Fatalf("Merge error: %v", err)
+	}
+
+	expected := &API{
+		Id:          "1",
+		CreatedAt:   100,
+		Name:        "app",
+		Hosts:       []string{"c.com"},
+		Uris:        []string{"/app"},
+		UpstreamUrl: "http://v2",
+		Retries:     5,
+	}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("Expect merged %+v, got %+v", expected, merged)
+	}
+
+	// Merging must not change a through shared slices
+	if !reflect.DeepEqual(current.Hosts, []string{"a.com", "b.com"}) {
+		t.Errorf("Hosts of current API are changed to %v", current.Hosts)
+	}
+	merged.Uris[0] = "/changed"
+	if current.Uris[0] != "/app" {
+		t.Errorf("Uris are shared between current and merged API")
+	}
+}
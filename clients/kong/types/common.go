@@ -0,0 +1,49 @@
+package types
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Ref refers to another entity by id
+type Ref struct {
+	Id string `json:"id"`
+}
+
+type ListOpts struct {
+	// Page size
+	Size int
+	// Offset returned by the previous page
+	Offset string
+	// Only list entities with all of the tags
+	Tags []string
+}
+
+func (opts *ListOpts) ToQuery() url.Values {
+	query := make(url.Values)
+	if nil == opts {
+		return query
+	}
+
+	if opts.Size > 0 {
+		query.Set("size", strconv.Itoa(opts.Size))
+	}
+	if "" != opts.Offset {
+		query.Set("offset", opts.Offset)
+	}
+	if len(opts.Tags) > 0 {
+		query.Set("tags", strings.Join(opts.Tags, ","))
+	}
+	return query
+}
+
+// Bool returns pointer of b, used to set optional boolean fields
+func Bool(b bool) *bool {
+	return &b
+}
+
+// Int returns pointer of i, used to set optional integer fields
+func Int(i int) *int {
+	return &i
+}
@@ -0,0 +1,65 @@
+package types
+
+type Consumer struct {
+	Id        string   `json:"id,omitempty"`
+	CreatedAt int64    `json:"created_at,omitempty"`
+	Username  string   `json:"username,omitempty"`
+	CustomId  string   `json:"custom_id,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+}
+
+// Credential fields shared by all kinds of credentials
+type CredentialMeta struct {
+	Id        string   `json:"id,omitempty"`
+	CreatedAt int64    `json:"created_at,omitempty"`
+	Consumer  *Ref     `json:"consumer,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	// Old versions refer to consumer by consumer_id
+	ConsumerId string `json:"consumer_id,omitempty"`
+}
+
+// Credential of key-auth plugin. Key is generated by Kong if empty.
+type KeyAuth struct {
+	CredentialMeta
+	Key string `json:"key,omitempty"`
+}
+
+// Credential of basic-auth plugin. Password returned by Kong is hashed.
+type BasicAuth struct {
+	CredentialMeta
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// Credential of jwt plugin. Key and secret are generated by Kong if empty.
+type JWTCredential struct {
+	CredentialMeta
+	Key    string `json:"key,omitempty"`
+	Secret string `json:"secret,omitempty"`
+	// One of HS256, HS384, HS512, RS256 and ES256, default HS256
+	Algorithm    string `json:"algorithm,omitempty"`
+	RsaPublicKey string `json:"rsa_public_key,omitempty"`
+}
+
+// Credential of hmac-auth plugin. Secret is generated by Kong if empty.
+type HMACAuth struct {
+	CredentialMeta
+	Username string `json:"username,omitempty"`
+	Secret   string `json:"secret,omitempty"`
+}
+
+// Credential of oauth2 plugin, i.e. an application of consumer.
+// Client id and secret are generated by Kong if empty.
+type OAuth2Credential struct {
+	CredentialMeta
+	Name         string   `json:"name,omitempty"`
+	ClientId     string   `json:"client_id,omitempty"`
+	ClientSecret string   `json:"client_secret,omitempty"`
+	RedirectUris []string `json:"redirect_uris,omitempty"`
+}
+
+// ACL group of consumer used by acl plugin
+type ACL struct {
+	CredentialMeta
+	Group string `json:"group,omitempty"`
+}
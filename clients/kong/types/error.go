@@ -25,6 +25,12 @@ func IsNotFoundErr(err error) bool {
 	return ok && kongErr.Status == http.StatusNotFound
 }
 
+// IsConflictErr returns true if the entity already exists
+func IsConflictErr(err error) bool {
+	kongErr, ok := err.(*Error)
+	return ok && kongErr.Status == http.StatusConflict
+}
+
 func ParseErr(resp *http.Response) *Error {
 	if resp.StatusCode >= http.StatusBadRequest {
 		msg, _ := ioutil.ReadAll(resp.Body)
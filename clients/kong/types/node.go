@@ -0,0 +1,76 @@
+package types
+
+import "encoding/json"
+
+// Health of targets
+const (
+	TargetHealthy         = "HEALTHY"
+	TargetUnhealthy       = "UNHEALTHY"
+	TargetHealthchecksOff = "HEALTHCHECKS_OFF"
+	TargetDnsError        = "DNS_ERROR"
+)
+
+// StringList is decoded from JSON array, or empty object which is how Kong may encode empty arrays
+type StringList []string
+
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '{' {
+		*l = nil
+		return nil
+	}
+
+	return json.Unmarshal(data, (*[]string)(l))
+}
+
+// NodeInfo is returned by the root endpoint of Admin API
+type NodeInfo struct {
+	Hostname   string      `json:"hostname"`
+	NodeId     string      `json:"node_id"`
+	Version    string      `json:"version"`
+	LuaVersion string      `json:"lua_version"`
+	Tagline    string      `json:"tagline"`
+	Plugins    NodePlugins `json:"plugins"`
+	Timers     *NodeTimers `json:"timers,omitempty"`
+	// Configuration of node, with sensitive values hidden by Kong
+	Configuration map[string]interface{} `json:"configuration"`
+}
+
+type NodePlugins struct {
+	// Plugins installed on the node. Values are true in old versions, or version and priority in new versions.
+	AvailableOnServer map[string]interface{} `json:"available_on_server"`
+	EnabledInCluster  StringList             `json:"enabled_in_cluster"`
+}
+
+type NodeTimers struct {
+	Running int `json:"running"`
+	Pending int `json:"pending"`
+}
+
+// NodeStatus is returned by the status endpoint of Admin API
+type NodeStatus struct {
+	Server   ServerStatus   `json:"server"`
+	Database DatabaseStatus `json:"database"`
+}
+
+// Counters of connections of Nginx
+type ServerStatus struct {
+	TotalRequests       int64 `json:"total_requests"`
+	ConnectionsActive   int64 `json:"connections_active"`
+	ConnectionsAccepted int64 `json:"connections_accepted"`
+	ConnectionsHandled  int64 `json:"connections_handled"`
+	ConnectionsReading  int64 `json:"connections_reading"`
+	ConnectionsWriting  int64 `json:"connections_writing"`
+	ConnectionsWaiting  int64 `json:"connections_waiting"`
+}
+
+type DatabaseStatus struct {
+	// Nil in old versions, which return counts of entities instead
+	Reachable *bool `json:"reachable,omitempty"`
+}
+
+// TargetHealth is health of target seen by the node
+type TargetHealth struct {
+	Target
+	// One of HEALTHY, UNHEALTHY, HEALTHCHECKS_OFF and DNS_ERROR
+	Health string `json:"health"`
+}
@@ -0,0 +1,134 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Names of bundled plugins with typed config
+const (
+	PluginRateLimiting = "rate-limiting"
+	PluginJWT          = "jwt"
+	PluginCORS         = "cors"
+)
+
+type Plugin struct {
+	Id        string                 `json:"id,omitempty"`
+	CreatedAt int64                  `json:"created_at,omitempty"`
+	Name      string                 `json:"name,omitempty"`
+	Config    map[string]interface{} `json:"config,omitempty"`
+	Enabled   *bool                  `json:"enabled,omitempty"`
+	Protocols []string               `json:"protocols,omitempty"`
+	Tags      []string               `json:"tags,omitempty"`
+	Service   *Ref                   `json:"service,omitempty"`
+	Route     *Ref                   `json:"route,omitempty"`
+	Consumer  *Ref                   `json:"consumer,omitempty"`
+	// Old versions refer to API and consumer by the following fields
+	ApiId      string `json:"api_id,omitempty"`
+	ConsumerId string `json:"consumer_id,omitempty"`
+}
+
+// DecodeConfig decodes config of plugin into conf, e.g. *RateLimitingConfig
+func (p *Plugin) DecodeConfig(conf interface{}) error {
+	data, err := json.Marshal(p.Config)
+	if nil != err {
+		return fmt.Errorf("Encode config error: %v", err)
+	}
+
+	if err := json.Unmarshal(data, conf); nil != err {
+		return fmt.Errorf("Decode config error: %v", err)
+	}
+
+	return nil
+}
+
+// PluginScope specifies which entity plugins are applied to.
+// At most one of the fields should be set, and plugins are global if none is set.
+type PluginScope struct {
+	Api      string
+	Service  string
+	Route    string
+	Consumer string
+}
+
+// PluginConfig is implemented by typed config of plugins
+type PluginConfig interface {
+	PluginName() string
+}
+
+// NewPlugin returns plugin with name and config specified by conf
+func NewPlugin(conf PluginConfig) (*Plugin, error) {
+	data, err := json.Marshal(conf)
+	if nil != err {
+		return nil, fmt.Errorf("Encode config error: %v", err)
+	}
+
+	p := &Plugin{
+		Name: conf.PluginName(),
+	}
+	if err := json.Unmarshal(data, &p.Config); nil != err {
+		return nil, fmt.Errorf("Decode config error: %v", err)
+	}
+
+	return p, nil
+}
+
+// Config of rate-limiting plugin. At least one of the limits should be set.
+type RateLimitingConfig struct {
+	Second int `json:"second,omitempty"`
+	Minute int `json:"minute,omitempty"`
+	Hour   int `json:"hour,omitempty"`
+	Day    int `json:"day,omitempty"`
+	Month  int `json:"month,omitempty"`
+	Year   int `json:"year,omitempty"`
+	// One of consumer, credential, ip, service and header
+	LimitBy    string `json:"limit_by,omitempty"`
+	HeaderName string `json:"header_name,omitempty"`
+	// One of local, cluster and redis
+	Policy            string `json:"policy,omitempty"`
+	FaultTolerant     *bool  `json:"fault_tolerant,omitempty"`
+	HideClientHeaders *bool  `json:"hide_client_headers,omitempty"`
+	RedisHost         string `json:"redis_host,omitempty"`
+	RedisPort         int    `json:"redis_port,omitempty"`
+	RedisPassword     string `json:"redis_password,omitempty"`
+	RedisTimeout      int    `json:"redis_timeout,omitempty"`
+	RedisDatabase     int    `json:"redis_database,omitempty"`
+}
+
+func (c *RateLimitingConfig) PluginName() string {
+	return PluginRateLimiting
+}
+
+// Config of jwt plugin
+type JWTConfig struct {
+	UriParamNames []string `json:"uri_param_names,omitempty"`
+	CookieNames   []string `json:"cookie_names,omitempty"`
+	HeaderNames   []string `json:"header_names,omitempty"`
+	// Claims to verify, i.e. exp and nbf
+	ClaimsToVerify []string `json:"claims_to_verify,omitempty"`
+	KeyClaimName   string   `json:"key_claim_name,omitempty"`
+	SecretIsBase64 *bool    `json:"secret_is_base64,omitempty"`
+	// Id of consumer used if authentication fails
+	Anonymous         string `json:"anonymous,omitempty"`
+	RunOnPreflight    *bool  `json:"run_on_preflight,omitempty"`
+	MaximumExpiration int    `json:"maximum_expiration,omitempty"`
+}
+
+func (c *JWTConfig) PluginName() string {
+	return PluginJWT
+}
+
+// Config of cors plugin
+type CORSConfig struct {
+	Origins           []string `json:"origins,omitempty"`
+	Methods           []string `json:"methods,omitempty"`
+	Headers           []string `json:"headers,omitempty"`
+	ExposedHeaders    []string `json:"exposed_headers,omitempty"`
+	Credentials       *bool    `json:"credentials,omitempty"`
+	MaxAge            int      `json:"max_age,omitempty"`
+	PreflightContinue *bool    `json:"preflight_continue,omitempty"`
+}
+
+func (c *CORSConfig) PluginName() string {
+	return PluginCORS
+}
@@ -0,0 +1,35 @@
+package types
+
+type Service struct {
+	Id             string   `json:"id,omitempty"`
+	CreatedAt      int64    `json:"created_at,omitempty"`
+	UpdatedAt      int64    `json:"updated_at,omitempty"`
+	Name           string   `json:"name,omitempty"`
+	Protocol       string   `json:"protocol,omitempty"`
+	Host           string   `json:"host,omitempty"`
+	Port           int      `json:"port,omitempty"`
+	Path           string   `json:"path,omitempty"`
+	Retries        int      `json:"retries,omitempty"`
+	ConnectTimeout int      `json:"connect_timeout,omitempty"`
+	WriteTimeout   int      `json:"write_timeout,omitempty"`
+	ReadTimeout    int      `json:"read_timeout,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	// Shorthand to set protocol, host, port and path, only used for creating and updating
+	Url string `json:"url,omitempty"`
+}
+
+type Route struct {
+	Id            string   `json:"id,omitempty"`
+	CreatedAt     int64    `json:"created_at,omitempty"`
+	UpdatedAt     int64    `json:"updated_at,omitempty"`
+	Name          string   `json:"name,omitempty"`
+	Protocols     []string `json:"protocols,omitempty"`
+	Methods       []string `json:"methods,omitempty"`
+	Hosts         []string `json:"hosts,omitempty"`
+	Paths         []string `json:"paths,omitempty"`
+	RegexPriority int      `json:"regex_priority,omitempty"`
+	StripPath     *bool    `json:"strip_path,omitempty"`
+	PreserveHost  *bool    `json:"preserve_host,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+	Service       *Ref     `json:"service,omitempty"`
+}
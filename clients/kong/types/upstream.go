@@ -0,0 +1,67 @@
+package types
+
+const (
+	AlgorithmRoundRobin        = "round-robin"
+	AlgorithmConsistentHashing = "consistent-hashing"
+	AlgorithmLeastConnections  = "least-connections"
+)
+
+type Upstream struct {
+	Id                 string        `json:"id,omitempty"`
+	CreatedAt          int64         `json:"created_at,omitempty"`
+	Name               string        `json:"name,omitempty"`
+	Algorithm          string        `json:"algorithm,omitempty"`
+	HashOn             string        `json:"hash_on,omitempty"`
+	HashFallback       string        `json:"hash_fallback,omitempty"`
+	HashOnHeader       string        `json:"hash_on_header,omitempty"`
+	HashFallbackHeader string        `json:"hash_fallback_header,omitempty"`
+	Slots              int           `json:"slots,omitempty"`
+	Healthchecks       *Healthchecks `json:"healthchecks,omitempty"`
+	Tags               []string      `json:"tags,omitempty"`
+}
+
+type Healthchecks struct {
+	Active  *ActiveHealthcheck  `json:"active,omitempty"`
+	Passive *PassiveHealthcheck `json:"passive,omitempty"`
+}
+
+type ActiveHealthcheck struct {
+	Type        string     `json:"type,omitempty"`
+	HttpPath    string     `json:"http_path,omitempty"`
+	Timeout     int        `json:"timeout,omitempty"`
+	Concurrency int        `json:"concurrency,omitempty"`
+	Healthy     *Healthy   `json:"healthy,omitempty"`
+	Unhealthy   *Unhealthy `json:"unhealthy,omitempty"`
+}
+
+type PassiveHealthcheck struct {
+	Type      string     `json:"type,omitempty"`
+	Healthy   *Healthy   `json:"healthy,omitempty"`
+	Unhealthy *Unhealthy `json:"unhealthy,omitempty"`
+}
+
+type Healthy struct {
+	Interval     int   `json:"interval,omitempty"`
+	HttpStatuses []int `json:"http_statuses,omitempty"`
+	Successes    int   `json:"successes,omitempty"`
+}
+
+type Unhealthy struct {
+	Interval     int   `json:"interval,omitempty"`
+	HttpStatuses []int `json:"http_statuses,omitempty"`
+	TcpFailures  int   `json:"tcp_failures,omitempty"`
+	Timeouts     int   `json:"timeouts,omitempty"`
+	HttpFailures int   `json:"http_failures,omitempty"`
+}
+
+type Target struct {
+	Id string `json:"id,omitempty"`
+	// Kong returns created_at of target in seconds with milliseconds fraction
+	CreatedAt float64 `json:"created_at,omitempty"`
+	// Address of target in form of host:port
+	Target string `json:"target,omitempty"`
+	// Kong's default weight is used if nil. Weight 0 disables the target.
+	Weight   *int     `json:"weight,omitempty"`
+	Upstream *Ref     `json:"upstream,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
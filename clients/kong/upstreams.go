@@ -0,0 +1,85 @@
+package kong
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/haborhuang/go-tools/clients/kong/types"
+)
+
+const upstreamsPath = "/upstreams"
+
+func upstreamPath(nameOrId string) string {
+	return path.Join(upstreamsPath, nameOrId)
+}
+
+func targetsPath(upstreamNameOrId string) string {
+	return path.Join(upstreamPath(upstreamNameOrId), "targets")
+}
+
+func targetPath(upstreamNameOrId, targetOrId string) string {
+	return path.Join(targetsPath(upstreamNameOrId), targetOrId)
+}
+
+func (c *Client) GetUpstream(nameOrId string) (*types.Upstream, error) {
+	var res *types.Upstream
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodGet).SubPath(upstreamPath(nameOrId)),
+	).intoJson(&res)
+
+	return res, err
+}
+
+// ListUpstreams returns a page of upstreams and offset of the next page
+func (c *Client) ListUpstreams(opts *types.ListOpts) ([]*types.Upstream, string, error) {
+	var res []*types.Upstream
+	next, err := c.list(upstreamsPath, opts.ToQuery(), &res)
+	return res, next, err
+}
+
+func (c *Client) AddUpstream(req *types.Upstream) (*types.Upstream, error) {
+	var res *types.Upstream
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPost).SubPath(upstreamsPath).JsonBody(req),
+	).intoJson(&res)
+
+	return res, err
+}
+
+func (c *Client) UpdateUpstream(nameOrId string, req *types.Upstream) (*types.Upstream, error) {
+	var res *types.Upstream
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPatch).SubPath(upstreamPath(nameOrId)).JsonBody(req),
+	).intoJson(&res)
+
+	return res, err
+}
+
+func (c *Client) DeleteUpstream(nameOrId string) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodDelete).SubPath(upstreamPath(nameOrId)),
+	).do()
+}
+
+// ListTargets returns a page of targets of the upstream and offset of the next page
+func (c *Client) ListTargets(upstreamNameOrId string, opts *types.ListOpts) ([]*types.Target, string, error) {
+	var res []*types.Target
+	next, err := c.list(targetsPath(upstreamNameOrId), opts.ToQuery(), &res)
+	return res, next, err
+}
+
+func (c *Client) AddTarget(upstreamNameOrId string, req *types.Target) (*types.Target, error) {
+	var res *types.Target
+	err := c.newResponse(
+		c.newRequest().Method(http.MethodPost).SubPath(targetsPath(upstreamNameOrId)).JsonBody(req),
+	).intoJson(&res)
+
+	return res, err
+}
+
+// DeleteTarget deletes target specified by address (host:port) or id
+func (c *Client) DeleteTarget(upstreamNameOrId, targetOrId string) error {
+	return c.newResponse(
+		c.newRequest().Method(http.MethodDelete).SubPath(targetPath(upstreamNameOrId, targetOrId)),
+	).do()
+}
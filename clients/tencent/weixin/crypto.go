@@ -0,0 +1,129 @@
+package weixin
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Block size of PKCS#7 padding used by Weixin, which is different from AES block size
+const msgPaddingSize = 32
+
+// Signature returns SHA1 of sorted and concatenated parts, which is how Weixin signs pushed requests,
+// i.e. token, timestamp and nonce in plain mode, plus encrypted message in safe mode.
+func Signature(parts ...string) string {
+	sorted := append([]string(nil), parts...)
+	sort.Strings(sorted)
+
+	return fmt.Sprintf("%x", sha1.Sum([]byte(strings.Join(sorted, ""))))
+}
+
+// verifySignature compares signature with Signature of parts in constant time
+func verifySignature(signature string, parts ...string) bool {
+	return subtle.ConstantTimeCompare([]byte(Signature(parts...)), []byte(signature)) == 1
+}
+
+// MsgCrypter verifies, decrypts and encrypts messages pushed by Weixin in safe mode
+type MsgCrypter struct {
+	token string
+	key   []byte
+	appId string
+}
+
+// NewMsgCrypter returns crypter with token and EncodingAESKey configured on Weixin,
+// and app id of official account or component
+func NewMsgCrypter(token, encodingAESKey, appId string) (*MsgCrypter, error) {
+	key, err := base64.StdEncoding.DecodeString(encodingAESKey + "=")
+	if nil != err {
+		return nil, fmt.Errorf("Decode EncodingAESKey error: %v", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("Invalid length of EncodingAESKey")
+	}
+
+	return &MsgCrypter{
+		token: token,
+		key:   key,
+		appId: appId,
+	}, nil
+}
+
+// Verify checks msg_signature of encrypted message
+func (c *MsgCrypter) Verify(signature, timestamp, nonce, encrypted string) bool {
+	return verifySignature(signature, c.token, timestamp, nonce, encrypted)
+}
+
+// Decrypt decrypts message and checks that it's sent to the app
+func (c *MsgCrypter) Decrypt(encrypted string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if nil != err {
+		return nil, fmt.Errorf("Decode encrypted message error: %v", err)
+	}
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, errors.New("Invalid length of encrypted message")
+	}
+
+	block, err := aes.NewCipher(c.key)
+	if nil != err {
+		return nil, err
+	}
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, c.key[:aes.BlockSize]).CryptBlocks(plain, data)
+
+	pad := int(plain[len(plain)-1])
+	if pad < 1 || pad > msgPaddingSize || pad > len(plain) {
+		return nil, errors.New("Invalid padding of message")
+	}
+	plain = plain[:len(plain)-pad]
+
+	// 16 bytes random, 4 bytes length of message, message and app id
+	if len(plain) < 20 {
+		return nil, errors.New("Invalid length of message")
+	}
+	// Compare before converting to int, which may overflow on 32-bit platforms
+	n := binary.BigEndian.Uint32(plain[16:20])
+	if uint64(n) > uint64(len(plain)-20) {
+		return nil, errors.New("Invalid length of message")
+	}
+	msg, appId := plain[20:20+int(n)], string(plain[20+int(n):])
+	if appId != c.appId {
+		return nil, fmt.Errorf("Message is sent to app '%s'", appId)
+	}
+
+	return msg, nil
+}
+
+// Encrypt encrypts message, e.g. reply in safe mode
+func (c *MsgCrypter) Encrypt(msg []byte) (string, error) {
+	buf := bytes.NewBuffer(nil)
+	random := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, random); nil != err {
+		return "", err
+	}
+	buf.Write(random)
+	binary.Write(buf, binary.BigEndian, uint32(len(msg)))
+	buf.Write(msg)
+	buf.WriteString(c.appId)
+
+	pad := msgPaddingSize - buf.Len()%msgPaddingSize
+	buf.Write(bytes.Repeat([]byte{byte(pad)}, pad))
+
+	block, err := aes.NewCipher(c.key)
+	if nil != err {
+		return "", err
+	}
+	data := buf.Bytes()
+	cipher.NewCBCEncrypter(block, c.key[:aes.BlockSize]).CryptBlocks(data, data)
+
+	return base64.StdEncoding.EncodeToString(data), nil
+}
@@ -0,0 +1,57 @@
+package weixin
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/binary"
+	"testing"
+)
+
+func TestMsgCrypter(t *testing.T) {
+	c, err := NewMsgCrypter(testToken, testAESKey, "app")
+	if nil != err {
+		t.Fatalf("New crypter error: %v", err)
+	}
+
+	encrypted, err := c.Encrypt([]byte("<xml></xml>"))
+	if nil != err {
+		t.Fatalf("Encrypt error: %v", err)
+	}
+	if !c.Verify(Signature(testToken, "1", "nonce", encrypted), "1", "nonce", encrypted) {
+		t.Errorf("Expect valid signature")
+	}
+	if c.Verify(Signature(testToken, "2", "nonce", encrypted), "1", "nonce", encrypted) {
+		t.Errorf("Expect invalid signature")
+	}
+
+	msg, err := c.Decrypt(encrypted)
+	if nil != err || string(msg) != "<xml></xml>" {
+		t.Fatalf("Unexpected message '%s', error: %v", msg, err)
+	}
+
+	other, _ := NewMsgCrypter(testToken, testAESKey, "other")
+	if _, err := other.Decrypt(encrypted); nil == err {
+		t.Errorf("Expect error of message sent to other app")
+	}
+}
+
+func TestDecryptInvalidLength(t *testing.T) {
+	c, _ := NewMsgCrypter(testToken, testAESKey, "app")
+
+	// Length of message overflows int on 32-bit platforms
+	buf := bytes.NewBuffer(make([]byte, 16))
+	binary.Write(buf, binary.BigEndian, uint32(0xffffffff))
+	buf.WriteString("app")
+	pad := msgPaddingSize - buf.Len()%msgPaddingSize
+	buf.Write(bytes.Repeat([]byte{byte(pad)}, pad))
+
+	block, _ := aes.NewCipher(c.key)
+	data := buf.Bytes()
+	cipher.NewCBCEncrypter(block, c.key[:aes.BlockSize]).CryptBlocks(data, data)
+
+	if _, err := c.Decrypt(base64.StdEncoding.EncodeToString(data)); nil == err {
+		t.Errorf("Expect error of invalid length")
+	}
+}
@@ -0,0 +1,151 @@
+package weixin
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/haborhuang/go-tools/clients/tencent/weixin/types"
+)
+
+// Max size of body of pushed requests
+const maxPushBodySize = 1 << 20
+
+// Component verify ticket is valid for 12 hours
+const verifyTicketTTL = 12 * time.Hour
+
+func verifyTicketKey(compAppid string) string {
+	return "component_verify_ticket:" + compAppid
+}
+
+// LoadVerifyTicket returns function reading the latest component verify ticket saved by CompEventHandler,
+// which can be used by NewCompTokenManager
+func LoadVerifyTicket(store TokenStore, compAppid string) func() (string, error) {
+	return func() (string, error) {
+		t, err := store.Get(verifyTicketKey(compAppid))
+		if nil != err {
+			return "", err
+		}
+		if !t.valid() {
+			return "", fmt.Errorf("Component verify ticket not received yet")
+		}
+
+		return t.Value, nil
+	}
+}
+
+// CompEventHandler receives events pushed to the authorization event URL of component.
+// Verify tickets are saved into store, and other events are passed to OnEvent.
+type CompEventHandler struct {
+	crypter   *MsgCrypter
+	store     TokenStore
+	compAppid string
+
+	// OnEvent is called with authorization events, i.e. authorized, unauthorized and updateauthorized.
+	// Weixin will push the event again if error is returned.
+	OnEvent func(e *types.CompEvent) error
+}
+
+// NewCompEventHandler returns handler with message token and EncodingAESKey of component
+func NewCompEventHandler(token, encodingAESKey, compAppid string, store TokenStore) (*CompEventHandler, error) {
+	crypter, err := NewMsgCrypter(token, encodingAESKey, compAppid)
+	if nil != err {
+		return nil, err
+	}
+
+	return &CompEventHandler{
+		crypter:   crypter,
+		store:     store,
+		compAppid: compAppid,
+	}, nil
+}
+
+func (h *CompEventHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !freshTimestamp(r.URL.Query().Get("timestamp")) {
+		http.Error(w, "Invalid timestamp", http.StatusForbidden)
+		return
+	}
+
+	data, status, err := decryptPush(h.crypter, r)
+	if nil != err {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	var e types.CompEvent
+	if err := xml.Unmarshal(data, &e); nil != err {
+		http.Error(w, fmt.Sprintf("Decode event error: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.handle(&e); nil != err {
+		// Error of handler is not exposed to the caller
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	io.WriteString(w, "success")
+}
+
+func (h *CompEventHandler) handle(e *types.CompEvent) error {
+	if e.InfoType == types.InfoTypeVerifyTicket {
+		createdAt := time.Now()
+		if e.CreateTime > 0 {
+			createdAt = time.Unix(e.CreateTime, 0)
+		}
+
+		key := verifyTicketKey(h.compAppid)
+		old, err := h.store.Get(key)
+		if nil != err {
+			return err
+		}
+		// Ticket pushed again or out of order doesn't override the newer one
+		if nil != old && old.ExpireAt.Add(-verifyTicketTTL).After(createdAt) {
+			return nil
+		}
+
+		return h.store.Set(key, &Token{
+			Value:    e.ComponentVerifyTicket,
+			ExpireAt: createdAt.Add(verifyTicketTTL),
+		})
+	}
+
+	if nil != h.OnEvent {
+		return h.OnEvent(e)
+	}
+	return nil
+}
+
+// decryptPush verifies signature of request pushed in safe mode and returns decrypted message.
+// Status code to respond is returned with error.
+func decryptPush(crypter *MsgCrypter, r *http.Request) ([]byte, int, error) {
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxPushBodySize))
+	if nil != err {
+		return nil, http.StatusBadRequest, fmt.Errorf("Read body error: %v", err)
+	}
+
+	var msg types.EncryptedMsg
+	if err := xml.Unmarshal(body, &msg); nil != err {
+		return nil, http.StatusBadRequest, fmt.Errorf("Decode body error: %v", err)
+	}
+
+	q := r.URL.Query()
+	if !crypter.Verify(q.Get("msg_signature"), q.Get("timestamp"), q.Get("nonce"), msg.Encrypt) {
+		return nil, http.StatusForbidden, fmt.Errorf("Invalid signature")
+	}
+
+	data, err := crypter.Decrypt(msg.Encrypt)
+	if nil != err {
+		return nil, http.StatusBadRequest, err
+	}
+
+	return data, http.StatusOK, nil
+}
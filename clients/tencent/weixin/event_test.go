@@ -0,0 +1,96 @@
+package weixin
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haborhuang/go-tools/clients/tencent/weixin/types"
+)
+
+const (
+	testToken  = "token"
+	testAESKey = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+)
+
+// newPush returns request pushed by Weixin in safe mode
+func newPush(t *testing.T, crypter *MsgCrypter, msg string) *http.Request {
+	return newPushAt(t, crypter, msg, time.Now())
+}
+
+// newPushAt returns request pushed at the time
+func newPushAt(t *testing.T, crypter *MsgCrypter, msg string, at time.Time) *http.Request {
+	encrypted, err := crypter.Encrypt([]byte(msg))
+	if nil != err {
+		t.Fatalf("Encrypt error: %v", err)
+	}
+
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	q := url.Values{}
+	q.Set("timestamp", timestamp)
+	q.Set("nonce", "nonce")
+	q.Set("encrypt_type", "aes")
+	q.Set("msg_signature", Signature(testToken, timestamp, "nonce", encrypted))
+	body := fmt.Sprintf("<xml><AppId><![CDATA[comp]]></AppId><Encrypt><![CDATA[%s]]></Encrypt></xml>", encrypted)
+
+	return httptest.NewRequest(http.MethodPost, "/events?"+q.Encode(), strings.NewReader(body))
+}
+
+func TestCompEventHandler(t *testing.T) {
+	store := NewMemoryStore()
+	h, err := NewCompEventHandler(testToken, testAESKey, "comp", store)
+	if nil != err {
+		t.Fatalf("New handler error: %v", err)
+	}
+	var events []*types.CompEvent
+	h.OnEvent = func(e *types.CompEvent) error {
+		events = append(events, e)
+		return nil
+	}
+
+	now := time.Now().Unix()
+	ticketFmt := "<xml><AppId>comp</AppId><CreateTime>%d</CreateTime><InfoType>component_verify_ticket</InfoType><ComponentVerifyTicket>%s</ComponentVerifyTicket></xml>"
+	msgs := []string{
+		fmt.Sprintf(ticketFmt, now, "ticket@1"),
+		"<xml><AppId>comp</AppId><InfoType>authorized</InfoType><AuthorizerAppid>app</AuthorizerAppid><AuthorizationCode>code</AuthorizationCode></xml>",
+		// Older ticket delivered late is ignored
+		fmt.Sprintf(ticketFmt, now-60, "ticket@0"),
+	}
+	for _, msg := range msgs {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newPush(t, h.crypter, msg))
+		if w.Code != http.StatusOK || w.Body.String() != "success" {
+			t.Fatalf("Unexpected response (%d)%s", w.Code, w.Body.String())
+		}
+	}
+
+	if ticket, err := LoadVerifyTicket(store, "comp")(); nil != err || ticket != "ticket@1" {
+		t.Errorf("Unexpected ticket '%s', error: %v", ticket, err)
+	}
+	if len(events) != 1 || events[0].AuthorizerAppid != "app" || events[0].AuthorizationCode != "code" {
+		t.Errorf("Unexpected events %+v", events)
+	}
+
+	req := newPush(t, h.crypter, msgs[0])
+	req.URL.RawQuery = strings.Replace(req.URL.RawQuery, "nonce=nonce", "nonce=other", 1)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expect forbidden with invalid signature but got %d", w.Code)
+	}
+
+	// Replayed push
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newPushAt(t, h.crypter, fmt.Sprintf(ticketFmt, now+1, "ticket@2"), time.Now().Add(-time.Hour)))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expect forbidden with stale timestamp but got %d", w.Code)
+	}
+	if ticket, _ := LoadVerifyTicket(store, "comp")(); ticket != "ticket@1" {
+		t.Errorf("Ticket is changed by replayed push: '%s'", ticket)
+	}
+}
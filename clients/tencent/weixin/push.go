@@ -0,0 +1,204 @@
+package weixin
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/haborhuang/go-tools/clients/tencent/weixin/types"
+)
+
+// Max difference between timestamp of pushed message and local time, to reject replayed messages
+const maxPushTimeSkew = 5 * time.Minute
+
+// MsgHandlerFunc handles message pushed to official account. The reply is sent to user if it's not nil.
+type MsgHandlerFunc func(msg *types.PushMsg) (*types.Reply, error)
+
+// MPMsgHandler receives messages and events pushed to official account in plain or safe mode,
+// and dispatches them to handlers by message type or event.
+type MPMsgHandler struct {
+	token string
+	// Nil in plain mode
+	crypter  *MsgCrypter
+	handlers map[string]MsgHandlerFunc
+
+	// Default handles messages without handler, which are ignored if it's nil
+	Default MsgHandlerFunc
+	// Compatible accepts plain messages in safe mode, i.e. compatible mode configured on Weixin.
+	// Otherwise only encrypted messages are accepted, since signature of plain messages doesn't cover body.
+	Compatible bool
+}
+
+// NewMPMsgHandler returns handler of messages in plain mode, with message token of official account
+func NewMPMsgHandler(token string) *MPMsgHandler {
+	return &MPMsgHandler{
+		token:    token,
+		handlers: make(map[string]MsgHandlerFunc),
+	}
+}
+
+// NewSafeMPMsgHandler returns handler of messages in safe mode, with message token,
+// EncodingAESKey and app id of official account. Replies are encrypted if messages are encrypted.
+// Set Compatible to accept plain messages as well.
+func NewSafeMPMsgHandler(token, encodingAESKey, appId string) (*MPMsgHandler, error) {
+	crypter, err := NewMsgCrypter(token, encodingAESKey, appId)
+	if nil != err {
+		return nil, err
+	}
+
+	h := NewMPMsgHandler(token)
+	h.crypter = crypter
+	return h, nil
+}
+
+// HandleMsg sets handler of messages of type, e.g. types.MsgTypeText
+func (h *MPMsgHandler) HandleMsg(msgType string, fn MsgHandlerFunc) {
+	h.handlers[strings.ToLower(msgType)] = fn
+}
+
+// HandleEvent sets handler of event, e.g. types.EventSubscribe
+func (h *MPMsgHandler) HandleEvent(event string, fn MsgHandlerFunc) {
+	h.handlers[types.MsgTypeEvent+":"+strings.ToLower(event)] = fn
+}
+
+func (h *MPMsgHandler) handler(msg *types.PushMsg) MsgHandlerFunc {
+	key := strings.ToLower(msg.MsgType)
+	if key == types.MsgTypeEvent {
+		key += ":" + strings.ToLower(msg.Event)
+	}
+
+	if fn, ok := h.handlers[key]; ok {
+		return fn
+	}
+	return h.Default
+}
+
+func (h *MPMsgHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	switch r.Method {
+	case http.MethodGet:
+		// Verification of server URL
+		if !verifySignature(q.Get("signature"), h.token, q.Get("timestamp"), q.Get("nonce")) {
+			http.Error(w, "Invalid signature", http.StatusForbidden)
+			return
+		}
+		io.WriteString(w, q.Get("echostr"))
+		return
+	case http.MethodPost:
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !freshTimestamp(q.Get("timestamp")) {
+		http.Error(w, "Invalid timestamp", http.StatusForbidden)
+		return
+	}
+
+	encrypted := q.Get("encrypt_type") == "aes"
+	var data []byte
+	if encrypted {
+		if nil == h.crypter {
+			http.Error(w, "Encrypted message is not supported", http.StatusBadRequest)
+			return
+		}
+
+		var status int
+		var err error
+		data, status, err = decryptPush(h.crypter, r)
+		if nil != err {
+			http.Error(w, err.Error(), status)
+			return
+		}
+	} else {
+		if nil != h.crypter && !h.Compatible {
+			http.Error(w, "Plain message is not allowed", http.StatusForbidden)
+			return
+		}
+		if !verifySignature(q.Get("signature"), h.token, q.Get("timestamp"), q.Get("nonce")) {
+			http.Error(w, "Invalid signature", http.StatusForbidden)
+			return
+		}
+
+		var err error
+		data, err = ioutil.ReadAll(io.LimitReader(r.Body, maxPushBodySize))
+		if nil != err {
+			http.Error(w, fmt.Sprintf("Read body error: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	var msg types.PushMsg
+	if err := xml.Unmarshal(data, &msg); nil != err {
+		http.Error(w, fmt.Sprintf("Decode message error: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	fn := h.handler(&msg)
+	if nil == fn {
+		io.WriteString(w, "success")
+		return
+	}
+
+	reply, err := fn(&msg)
+	if nil != err {
+		// Error of handler is not exposed to the caller
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if nil == reply {
+		io.WriteString(w, "success")
+		return
+	}
+
+	data, err = h.encodeReply(reply, encrypted, q.Get("nonce"))
+	if nil != err {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Write(data)
+}
+
+// freshTimestamp returns true if timestamp in seconds is close to local time
+func freshTimestamp(timestamp string) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if nil != err {
+		return false
+	}
+
+	skew := time.Since(time.Unix(ts, 0))
+	return skew <= maxPushTimeSkew && skew >= -maxPushTimeSkew
+}
+
+func (h *MPMsgHandler) encodeReply(reply *types.Reply, encrypted bool, nonce string) ([]byte, error) {
+	data, err := xml.Marshal(reply)
+	if nil != err {
+		return nil, fmt.Errorf("Encode reply error: %v", err)
+	}
+	if !encrypted {
+		return data, nil
+	}
+
+	enc, err := h.crypter.Encrypt(data)
+	if nil != err {
+		return nil, fmt.Errorf("Encrypt reply error: %v", err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	data, err = xml.Marshal(&types.EncryptedReply{
+		Encrypt:      enc,
+		MsgSignature: Signature(h.token, timestamp, nonce, enc),
+		TimeStamp:    timestamp,
+		Nonce:        nonce,
+	})
+	if nil != err {
+		return nil, fmt.Errorf("Encode encrypted reply error: %v", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,116 @@
+package weixin
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haborhuang/go-tools/clients/tencent/weixin/types"
+)
+
+const testTextMsg = "<xml><ToUserName>gh_mp</ToUserName><FromUserName>user</FromUserName><CreateTime>1500000000</CreateTime>" +
+	"<MsgType>text</MsgType><Content>hello</Content><MsgId>1</MsgId></xml>"
+
+func echo(msg *types.PushMsg) (*types.Reply, error) {
+	return types.NewTextReply(msg, msg.Content), nil
+}
+
+// newPlainPush returns request pushed by Weixin in plain mode at the time
+func newPlainPush(at time.Time, msg string) *http.Request {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	q := url.Values{}
+	q.Set("timestamp", timestamp)
+	q.Set("nonce", "nonce")
+	q.Set("signature", Signature(testToken, timestamp, "nonce"))
+
+	return httptest.NewRequest(http.MethodPost, "/mp?"+q.Encode(), strings.NewReader(msg))
+}
+
+func TestMPMsgHandlerPlain(t *testing.T) {
+	h := NewMPMsgHandler(testToken)
+	h.HandleMsg(types.MsgTypeText, echo)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newPlainPush(time.Now(), testTextMsg))
+
+	var reply types.Reply
+	if err := xml.Unmarshal(w.Body.Bytes(), &reply); nil != err {
+		t.Fatalf("Decode reply error: %v, (%d)%s", err, w.Code, w.Body.String())
+	}
+	if reply.ToUserName != "user" || reply.FromUserName != "gh_mp" || reply.Content != "hello" {
+		t.Errorf("Unexpected reply %+v", reply)
+	}
+}
+
+func TestMPMsgHandlerSafe(t *testing.T) {
+	h, err := NewSafeMPMsgHandler(testToken, testAESKey, "comp")
+	if nil != err {
+		t.Fatalf("New handler error: %v", err)
+	}
+	h.HandleMsg(types.MsgTypeText, echo)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newPush(t, h.crypter, testTextMsg))
+
+	var enc types.EncryptedReply
+	if err := xml.Unmarshal(w.Body.Bytes(), &enc); nil != err {
+		t.Fatalf("Decode reply error: %v, (%d)%s", err, w.Code, w.Body.String())
+	}
+	if !h.crypter.Verify(enc.MsgSignature, enc.TimeStamp, enc.Nonce, enc.Encrypt) {
+		t.Fatalf("Invalid signature of reply")
+	}
+	data, err := h.crypter.Decrypt(enc.Encrypt)
+	if nil != err {
+		t.Fatalf("Decrypt reply error: %v", err)
+	}
+
+	var reply types.Reply
+	if err := xml.Unmarshal(data, &reply); nil != err || reply.Content != "hello" {
+		t.Errorf("Unexpected reply %s, error: %v", data, err)
+	}
+}
+
+func TestMPMsgHandlerRejected(t *testing.T) {
+	h, err := NewSafeMPMsgHandler(testToken, testAESKey, "comp")
+	if nil != err {
+		t.Fatalf("New handler error: %v", err)
+	}
+	h.HandleMsg(types.MsgTypeText, echo)
+
+	// Plain message in safe mode
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newPlainPush(time.Now(), testTextMsg))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expect plain message rejected, got (%d)%s", w.Code, w.Body.String())
+	}
+
+	h.Compatible = true
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newPlainPush(time.Now(), testTextMsg))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expect plain message accepted in compatible mode, got (%d)%s", w.Code, w.Body.String())
+	}
+
+	// Replayed message
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newPlainPush(time.Now().Add(-time.Hour), testTextMsg))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expect stale message rejected, got (%d)%s", w.Code, w.Body.String())
+	}
+
+	// Error of handler is not exposed
+	h.HandleMsg(types.MsgTypeText, func(msg *types.PushMsg) (*types.Reply, error) {
+		return nil, fmt.Errorf("database password is wrong")
+	})
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newPush(t, h.crypter, testTextMsg))
+	if w.Code != http.StatusInternalServerError || strings.Contains(w.Body.String(), "password") {
+		t.Errorf("Unexpected response (%d)%s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,180 @@
+package weixin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// TokenStore keeps tokens by key, which can be shared by processes, e.g. file and Redis.
+// Get returns nil without error if the token is not found.
+type TokenStore interface {
+	Get(key string) (*Token, error)
+	Set(key string, token *Token) error
+}
+
+// MemoryStore keeps tokens in memory of process
+type MemoryStore struct {
+	lock   sync.RWMutex
+	tokens map[string]Token
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		tokens: make(map[string]Token),
+	}
+}
+
+func (s *MemoryStore) Get(key string) (*Token, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	t, ok := s.tokens[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return &t, nil
+}
+
+func (s *MemoryStore) Set(key string, token *Token) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.tokens[key] = *token
+	return nil
+}
+
+// FileStore keeps each token in a JSON file under the directory
+type FileStore struct {
+	dir  string
+	lock sync.Mutex
+}
+
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{
+		dir: dir,
+	}
+}
+
+func (s *FileStore) path(key string) string {
+	return filepath.Join(s.dir, url.PathEscape(key)+".json")
+}
+
+func (s *FileStore) Get(key string) (*Token, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	data, err := ioutil.ReadFile(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if nil != err {
+		return nil, fmt.Errorf("Read token file error: %v", err)
+	}
+
+	var t Token
+	if err := json.Unmarshal(data, &t); nil != err {
+		return nil, fmt.Errorf("Decode token file error: %v", err)
+	}
+
+	return &t, nil
+}
+
+// Set writes token into a temporary file and then renames it, so that readers never see partial files
+func (s *FileStore) Set(key string, token *Token) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	data, err := json.Marshal(token)
+	if nil != err {
+		return fmt.Errorf("Encode token error: %v", err)
+	}
+
+	if err := os.MkdirAll(s.dir, 0700); nil != err {
+		return fmt.Errorf("Create directory error: %v", err)
+	}
+	f, err := ioutil.TempFile(s.dir, ".token-")
+	if nil != err {
+		return fmt.Errorf("Create temporary file error: %v", err)
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(data)
+	if closeErr := f.Close(); nil == err {
+		err = closeErr
+	}
+	if nil != err {
+		return fmt.Errorf("Write token file error: %v", err)
+	}
+
+	if err := os.Rename(f.Name(), s.path(key)); nil != err {
+		return fmt.Errorf("Save token file error: %v", err)
+	}
+
+	return nil
+}
+
+// RedisClient includes the Redis commands used by RedisStore, which can be implemented by adapters of any Redis client.
+// Get should return empty string without error if the key doesn't exist, and Set with zero ttl means no expiration.
+type RedisClient interface {
+	Get(key string) (string, error)
+	Set(key, value string, ttl time.Duration) error
+}
+
+// RedisStore keeps tokens in Redis as JSON with keys prefixed
+type RedisStore struct {
+	client RedisClient
+	prefix string
+}
+
+func NewRedisStore(client RedisClient, prefix string) *RedisStore {
+	return &RedisStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *RedisStore) Get(key string) (*Token, error) {
+	data, err := s.client.Get(s.prefix + key)
+	if nil != err {
+		return nil, fmt.Errorf("Get token from redis error: %v", err)
+	}
+	if data == "" {
+		return nil, nil
+	}
+
+	var t Token
+	if err := json.Unmarshal([]byte(data), &t); nil != err {
+		return nil, fmt.Errorf("Decode token error: %v", err)
+	}
+
+	return &t, nil
+}
+
+// Set saves token until it expires. Tokens with refresh token never expire, since refresh token outlives access token.
+func (s *RedisStore) Set(key string, token *Token) error {
+	data, err := json.Marshal(token)
+	if nil != err {
+		return fmt.Errorf("Encode token error: %v", err)
+	}
+
+	var ttl time.Duration
+	if token.RefreshToken == "" {
+		ttl = time.Until(token.ExpireAt)
+		if ttl <= 0 {
+			return nil
+		}
+	}
+
+	if err := s.client.Set(s.prefix+key, string(data), ttl); nil != err {
+		return fmt.Errorf("Save token into redis error: %v", err)
+	}
+
+	return nil
+}
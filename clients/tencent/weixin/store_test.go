@@ -0,0 +1,88 @@
+package weixin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStore(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "weixin-")
+	defer os.RemoveAll(dir)
+	s := NewFileStore(filepath.Join(dir, "tokens"))
+
+	key := authTokenKey("comp", "app/1")
+	if token, err := s.Get(key); nil != err || nil != token {
+		t.Fatalf("Expect no token, got %+v, error: %v", token, err)
+	}
+
+	token := &Token{Value: "token", ExpireAt: time.Now().Add(time.Hour).Round(time.Second), RefreshToken: "refresh"}
+	if err := s.Set(key, token); nil != err {
+		t.Fatalf("Set token error: %v", err)
+	}
+	got, err := s.Get(key)
+	if nil != err {
+		t.Fatalf("Get token error: %v", err)
+	}
+	if got.Value != token.Value || got.RefreshToken != token.RefreshToken || !got.ExpireAt.Equal(token.ExpireAt) {
+		t.Errorf("Expect token %+v, got %+v", token, got)
+	}
+
+	// Key is escaped into a single file without temporary files left
+	files, _ := ioutil.ReadDir(filepath.Join(dir, "tokens"))
+	if len(files) != 1 {
+		t.Errorf("Expect 1 file, got %d", len(files))
+	}
+}
+
+type fakeRedis struct {
+	values map[string]string
+	ttls   map[string]time.Duration
+}
+
+func (r *fakeRedis) Get(key string) (string, error) {
+	return r.values[key], nil
+}
+
+func (r *fakeRedis) Set(key, value string, ttl time.Duration) error {
+	r.values[key] = value
+	r.ttls[key] = ttl
+	return nil
+}
+
+func TestRedisStore(t *testing.T) {
+	r := &fakeRedis{values: make(map[string]string), ttls: make(map[string]time.Duration)}
+	s := NewRedisStore(r, "weixin:")
+
+	if token, err := s.Get("comp"); nil != err || nil != token {
+		t.Fatalf("Expect no token, got %+v, error: %v", token, err)
+	}
+
+	if err := s.Set("comp", NewToken("comp-token", 7200)); nil != err {
+		t.Fatalf("Set token error: %v", err)
+	}
+	if ttl := r.ttls["weixin:comp"]; ttl <= 0 || ttl > 7200*time.Second {
+		t.Errorf("Unexpected ttl %v", ttl)
+	}
+	if token, err := s.Get("comp"); nil != err || token.Value != "comp-token" {
+		t.Errorf("Unexpected token %+v, error: %v", token, err)
+	}
+
+	// Tokens with refresh token never expire
+	if err := s.Set("auth", &Token{Value: "auth-token", ExpireAt: time.Now().Add(time.Hour), RefreshToken: "refresh"}); nil != err {
+		t.Fatalf("Set token error: %v", err)
+	}
+	if ttl, ok := r.ttls["weixin:auth"]; !ok || ttl != 0 {
+		t.Errorf("Expect no expiration, got %v", ttl)
+	}
+
+	// Expired tokens are not saved
+	if err := s.Set("expired", &Token{Value: "expired", ExpireAt: time.Now().Add(-time.Second)}); nil != err {
+		t.Fatalf("Set token error: %v", err)
+	}
+	if _, ok := r.values["weixin:expired"]; ok {
+		t.Errorf("Expect expired token not saved")
+	}
+}
@@ -0,0 +1,248 @@
+package weixin
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/haborhuang/go-tools/clients/tencent/weixin/types"
+)
+
+// Default duration to refresh token before it expires
+const DefaultRefreshAhead = 5 * time.Minute
+
+// Token is access token with expiry kept in TokenStore
+type Token struct {
+	Value    string    `json:"value"`
+	ExpireAt time.Time `json:"expire_at"`
+	// Refresh token of authorizer access token
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// NewToken returns token expiring after expiresIn seconds
+func NewToken(value string, expiresIn int64) *Token {
+	return &Token{
+		Value:    value,
+		ExpireAt: time.Now().Add(time.Duration(expiresIn) * time.Second),
+	}
+}
+
+// NewAuthToken returns token of authorizer with refresh token, e.g. got by QueryAuth
+func NewAuthToken(t *types.AuthToken) *Token {
+	token := NewToken(t.AccessToken, t.ExpiresIn)
+	token.RefreshToken = t.RefreshToken
+	return token
+}
+
+func (t *Token) valid() bool {
+	return nil != t && t.Value != "" && time.Now().Before(t.ExpireAt)
+}
+
+// fresh returns true if t is valid and needn't be refreshed yet
+func (m *TokenManager) fresh(t *Token) bool {
+	return t.valid() && time.Now().Add(m.refreshAhead).Before(t.ExpireAt)
+}
+
+// TokenManager caches token in store and refreshes it before it expires.
+// Concurrent refreshes in the same process are merged into one.
+type TokenManager struct {
+	store        TokenStore
+	key          string
+	fetch        func() (*Token, error)
+	refreshAhead time.Duration
+
+	lock sync.Mutex
+	// Refresh in progress shared by callers
+	inflight *refreshCall
+}
+
+type refreshCall struct {
+	done  chan struct{}
+	token *Token
+	err   error
+}
+
+// NewTokenManager returns manager of token saved in store with key. fetch is called to get a new token.
+// The token is refreshed in background once it expires within refreshAhead.
+func NewTokenManager(store TokenStore, key string, fetch func() (*Token, error), refreshAhead time.Duration) *TokenManager {
+	return &TokenManager{
+		store:        store,
+		key:          key,
+		fetch:        fetch,
+		refreshAhead: refreshAhead,
+	}
+}
+
+// Token returns cached token, or a new one if it's not cached or expired
+func (m *TokenManager) Token() (string, error) {
+	t, err := m.store.Get(m.key)
+	if nil != err {
+		return "", err
+	}
+
+	if t.valid() {
+		if !m.fresh(t) {
+			// Errors are ignored and refresh will be retried by the next call
+			m.startRefresh(t.Value)
+		}
+		return t.Value, nil
+	}
+
+	var stale string
+	if nil != t {
+		stale = t.Value
+	}
+	return m.refresh(stale)
+}
+
+// Refresh fetches a new token and saves it into store
+func (m *TokenManager) Refresh() (string, error) {
+	var stale string
+	if t, err := m.store.Get(m.key); nil == err && nil != t {
+		stale = t.Value
+	}
+
+	return m.refresh(stale)
+}
+
+// refresh waits for a new token to replace the stale one
+func (m *TokenManager) refresh(stale string) (string, error) {
+	call := m.startRefresh(stale)
+	<-call.done
+	if nil != call.err {
+		return "", call.err
+	}
+
+	return call.token.Value, nil
+}
+
+// Set saves token into store, e.g. authorizer token got by QueryAuth
+func (m *TokenManager) Set(token *Token) error {
+	return m.store.Set(m.key, token)
+}
+
+// startRefresh starts refresh of the stale token in background, or returns the one in progress.
+// The store is checked again before fetching, so that a refresh which has just finished is not repeated.
+func (m *TokenManager) startRefresh(stale string) *refreshCall {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if nil != m.inflight {
+		return m.inflight
+	}
+
+	call := &refreshCall{
+		done: make(chan struct{}),
+	}
+	m.inflight = call
+
+	go func() {
+		if t, err := m.store.Get(m.key); nil == err && m.fresh(t) && t.Value != stale {
+			call.token = t
+		} else {
+			call.token, call.err = m.fetch()
+			if nil == call.err {
+				call.err = m.store.Set(m.key, call.token)
+			}
+		}
+
+		m.lock.Lock()
+		m.inflight = nil
+		m.lock.Unlock()
+		close(call.done)
+	}()
+
+	return call
+}
+
+// renew refreshes token unless it has been changed from old by others
+func (m *TokenManager) renew(old string) (string, error) {
+	t, err := m.store.Get(m.key)
+	if nil == err && t.valid() && t.Value != old {
+		return t.Value, nil
+	}
+
+	return m.refresh(old)
+}
+
+// Do calls fn with token, and calls it again with a new token if fn returns expired token error,
+// i.e. *types.ErrRes of which IsExpiredTokenErr returns true
+func (m *TokenManager) Do(fn func(token string) error) error {
+	token, err := m.Token()
+	if nil != err {
+		return err
+	}
+
+	err = fn(token)
+	if errRes, ok := err.(*types.ErrRes); !ok || !errRes.IsExpiredTokenErr() {
+		return err
+	}
+
+	token, err = m.renew(token)
+	if nil != err {
+		return err
+	}
+
+	return fn(token)
+}
+
+func compTokenKey(compAppid string) string {
+	return "component_access_token:" + compAppid
+}
+
+func authTokenKey(compAppid, appId string) string {
+	return fmt.Sprintf("authorizer_access_token:%s:%s", compAppid, appId)
+}
+
+// NewCompTokenManager returns manager of component access token. ticket should return the latest component verify ticket.
+func (c *CompClient) NewCompTokenManager(store TokenStore, ticket func() (string, error)) *TokenManager {
+	return NewTokenManager(store, compTokenKey(c.compAppid), func() (*Token, error) {
+		t, err := ticket()
+		if nil != err {
+			return nil, fmt.Errorf("Get component verify ticket error: %v", err)
+		}
+
+		res, err := c.GetCompAccessToken(t)
+		if nil != err {
+			return nil, err
+		}
+		if err := res.Err(); nil != err {
+			return nil, err
+		}
+
+		return NewToken(res.ComponentAccessToken, res.ExpiresIn), nil
+	}, DefaultRefreshAhead)
+}
+
+// NewAuthTokenManager returns manager of access token of authorizer, using comp to get component access token.
+// Refresh token of authorizer is read from store, so the token got by QueryAuth should be saved by Set at first.
+func (c *CompClient) NewAuthTokenManager(store TokenStore, comp *TokenManager, appId string) *TokenManager {
+	key := authTokenKey(c.compAppid, appId)
+	return NewTokenManager(store, key, func() (*Token, error) {
+		old, err := store.Get(key)
+		if nil != err {
+			return nil, err
+		}
+		if nil == old || old.RefreshToken == "" {
+			return nil, fmt.Errorf("Refresh token of authorizer '%s' not found", appId)
+		}
+
+		var res *types.AuthTokenRes
+		err = comp.Do(func(compToken string) error {
+			res, err = c.NewCertifiedCompClient(compToken).GetAuthToken(appId, old.RefreshToken)
+			if nil != err {
+				return err
+			}
+			return res.Err()
+		})
+		if nil != err {
+			return nil, err
+		}
+
+		t := NewAuthToken(&res.AuthToken)
+		if t.RefreshToken == "" {
+			t.RefreshToken = old.RefreshToken
+		}
+		return t, nil
+	}, DefaultRefreshAhead)
+}
@@ -0,0 +1,126 @@
+package weixin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/haborhuang/go-tools/clients/tencent/weixin/types"
+)
+
+func TestTokenManager(t *testing.T) {
+	var fetches int32
+	m := NewTokenManager(NewMemoryStore(), "test", func() (*Token, error) {
+		n := atomic.AddInt32(&fetches, 1)
+		time.Sleep(10 * time.Millisecond)
+		return NewToken(fmt.Sprintf("token-%d", n), 7200), nil
+	}, DefaultRefreshAhead)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if token, err := m.Token(); nil != err || token != "token-1" {
+				t.Errorf("Unexpected token '%s', error: %v", token, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if fetches != 1 {
+		t.Fatalf("Expect 1 fetch but got %d", fetches)
+	}
+
+	var used []string
+	err := m.Do(func(token string) error {
+		used = append(used, token)
+		if token == "token-1" {
+			return &types.ErrRes{ErrCode: 42001, ErrMsg: "access_token expired"}
+		}
+		return nil
+	})
+	if nil != err || len(used) != 2 || used[1] != "token-2" {
+		t.Fatalf("Unexpected tokens %v, error: %v", used, err)
+	}
+}
+
+func TestTokenManagerLateRefresh(t *testing.T) {
+	var fetches int32
+	store := NewMemoryStore()
+	m := NewTokenManager(store, "test", func() (*Token, error) {
+		n := atomic.AddInt32(&fetches, 1)
+		return NewToken(fmt.Sprintf("token-%d", n), 7200), nil
+	}, DefaultRefreshAhead)
+
+	// Token is about to expire, so it's refreshed in background
+	store.Set("test", NewToken("token-0", 60))
+	if token, err := m.Token(); nil != err || token != "token-0" {
+		t.Fatalf("Unexpected token '%s', error: %v", token, err)
+	}
+	<-m.startRefresh("token-0").done
+
+	// Refresh of the stale token arriving after the one finished uses the new token
+	if token, err := m.refresh("token-0"); nil != err || token != "token-1" {
+		t.Fatalf("Unexpected token '%s', error: %v", token, err)
+	}
+	if fetches != 1 {
+		t.Fatalf("Expect 1 fetch but got %d", fetches)
+	}
+
+	// Explicit refresh always fetches
+	if token, err := m.Refresh(); nil != err || token != "token-2" {
+		t.Fatalf("Unexpected token '%s', error: %v", token, err)
+	}
+}
+
+func TestAuthTokenManager(t *testing.T) {
+	var refreshToken string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/component/api_component_token":
+			json.NewEncoder(w).Encode(&types.ComponentTokenRes{ComponentAccessToken: "comp-token", ExpiresIn: 7200})
+		case "/component/api_authorizer_token":
+			var req authTokenReq
+			json.NewDecoder(r.Body).Decode(&req)
+			if r.URL.Query().Get("component_access_token") != "comp-token" || req.Appid != "app" {
+				t.Errorf("Unexpected request %s: %+v", r.URL, req)
+			}
+			refreshToken = req.RefreshToken
+			json.NewEncoder(w).Encode(&types.AuthTokenRes{AuthToken: types.AuthToken{AccessToken: "auth-token", ExpiresIn: 7200}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+
+	u, _ := url.Parse(s.URL)
+	c := (&Client{url: u}).NewCompClient("comp", "secret")
+	store := NewMemoryStore()
+	comp := c.NewCompTokenManager(store, func() (string, error) {
+		return "ticket", nil
+	})
+	m := c.NewAuthTokenManager(store, comp, "app")
+
+	if _, err := m.Token(); nil == err {
+		t.Fatalf("Expect error without refresh token")
+	}
+
+	if err := m.Set(&Token{Value: "expired", ExpireAt: time.Now(), RefreshToken: "refresh"}); nil != err {
+		t.Fatalf("Set token error: %v", err)
+	}
+	token, err := m.Token()
+	if nil != err || token != "auth-token" || refreshToken != "refresh" {
+		t.Fatalf("Unexpected token '%s' refreshed by '%s', error: %v", token, refreshToken, err)
+	}
+
+	// Refresh token is kept if not returned
+	if saved, _ := store.Get(authTokenKey("comp", "app")); nil == saved || saved.RefreshToken != "refresh" {
+		t.Errorf("Unexpected saved token %+v", saved)
+	}
+}
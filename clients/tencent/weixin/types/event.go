@@ -0,0 +1,34 @@
+package types
+
+import "encoding/xml"
+
+// Info types of component events
+const (
+	InfoTypeVerifyTicket     = "component_verify_ticket"
+	InfoTypeAuthorized       = "authorized"
+	InfoTypeUnauthorized     = "unauthorized"
+	InfoTypeUpdateAuthorized = "updateauthorized"
+)
+
+// EncryptedMsg is the envelope of messages pushed in safe mode
+type EncryptedMsg struct {
+	XMLName    xml.Name `xml:"xml"`
+	AppId      string   `xml:"AppId,omitempty"`
+	ToUserName string   `xml:"ToUserName,omitempty"`
+	Encrypt    string   `xml:"Encrypt"`
+}
+
+// CompEvent is pushed to component, including verify ticket and authorization events
+type CompEvent struct {
+	XMLName    xml.Name `xml:"xml"`
+	AppId      string   `xml:"AppId"`
+	CreateTime int64    `xml:"CreateTime"`
+	InfoType   string   `xml:"InfoType"`
+	// Set if InfoType is component_verify_ticket
+	ComponentVerifyTicket string `xml:"ComponentVerifyTicket"`
+	// The following fields are set for authorization events
+	AuthorizerAppid              string `xml:"AuthorizerAppid"`
+	AuthorizationCode            string `xml:"AuthorizationCode"`
+	AuthorizationCodeExpiredTime int64  `xml:"AuthorizationCodeExpiredTime"`
+	PreAuthCode                  string `xml:"PreAuthCode"`
+}
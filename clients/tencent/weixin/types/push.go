@@ -0,0 +1,117 @@
+package types
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Types of messages pushed to official account
+const (
+	MsgTypeText     = "text"
+	MsgTypeImage    = "image"
+	MsgTypeVoice    = "voice"
+	MsgTypeLocation = "location"
+	MsgTypeEvent    = "event"
+)
+
+// Events pushed to official account
+const (
+	EventSubscribe   = "subscribe"
+	EventUnsubscribe = "unsubscribe"
+	EventScan        = "SCAN"
+	EventLocation    = "LOCATION"
+	EventClick       = "CLICK"
+	EventView        = "VIEW"
+)
+
+// PushMsg is message or event pushed to official account. Fields are set according to MsgType and Event.
+type PushMsg struct {
+	XMLName      xml.Name `xml:"xml"`
+	ToUserName   string   `xml:"ToUserName"`
+	FromUserName string   `xml:"FromUserName"`
+	CreateTime   int64    `xml:"CreateTime"`
+	MsgType      string   `xml:"MsgType"`
+	MsgId        int64    `xml:"MsgId"`
+
+	// Text message
+	Content string `xml:"Content"`
+
+	// Image and voice messages
+	MediaId string `xml:"MediaId"`
+	PicUrl  string `xml:"PicUrl"`
+	Format  string `xml:"Format"`
+	// Result of speech recognition of voice, if enabled
+	Recognition string `xml:"Recognition"`
+
+	// Location message
+	LocationX float64 `xml:"Location_X"`
+	LocationY float64 `xml:"Location_Y"`
+	Scale     int     `xml:"Scale"`
+	Label     string  `xml:"Label"`
+
+	// Events
+	Event string `xml:"Event"`
+	// Key of menu of CLICK event, URL of VIEW event, or scene of SCAN and subscribe events by QR code
+	EventKey string `xml:"EventKey"`
+	// Ticket of QR code
+	Ticket string `xml:"Ticket"`
+
+	// LOCATION event
+	Latitude  float64 `xml:"Latitude"`
+	Longitude float64 `xml:"Longitude"`
+	Precision float64 `xml:"Precision"`
+}
+
+// Reply to pushed message, built by NewTextReply, NewImageReply and NewVoiceReply
+type Reply struct {
+	XMLName      xml.Name    `xml:"xml"`
+	ToUserName   string      `xml:"ToUserName"`
+	FromUserName string      `xml:"FromUserName"`
+	CreateTime   int64       `xml:"CreateTime"`
+	MsgType      string      `xml:"MsgType"`
+	Content      string      `xml:"Content,omitempty"`
+	Image        *ReplyMedia `xml:"Image,omitempty"`
+	Voice        *ReplyMedia `xml:"Voice,omitempty"`
+}
+
+type ReplyMedia struct {
+	MediaId string `xml:"MediaId"`
+}
+
+func newReply(msg *PushMsg, msgType string) *Reply {
+	return &Reply{
+		ToUserName:   msg.FromUserName,
+		FromUserName: msg.ToUserName,
+		CreateTime:   time.Now().Unix(),
+		MsgType:      msgType,
+	}
+}
+
+func NewTextReply(msg *PushMsg, content string) *Reply {
+	r := newReply(msg, MsgTypeText)
+	r.Content = content
+	return r
+}
+
+// NewImageReply replies image uploaded as media
+func NewImageReply(msg *PushMsg, mediaId string) *Reply {
+	r := newReply(msg, MsgTypeImage)
+	r.Image = &ReplyMedia{MediaId: mediaId}
+	return r
+}
+
+// NewVoiceReply replies voice uploaded as media
+func NewVoiceReply(msg *PushMsg, mediaId string) *Reply {
+	r := newReply(msg, MsgTypeVoice)
+	r.Voice = &ReplyMedia{MediaId: mediaId}
+	return r
+}
+
+// EncryptedReply is the envelope of reply in safe mode
+type EncryptedReply struct {
+	XMLName      xml.Name `xml:"xml"`
+	Encrypt      string   `xml:"Encrypt"`
+	MsgSignature string   `xml:"MsgSignature"`
+	TimeStamp    string   `xml:"TimeStamp"`
+	Nonce        string   `xml:"Nonce"`
+}
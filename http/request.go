@@ -32,6 +32,8 @@ type HttpRequest struct {
 	resp *http.Response
 	// Debug flag
 	debug bool
+	// HTTP client to send request
+	client *http.Client
 }
 
 // New HttpRequest object with specified URL
@@ -78,6 +80,25 @@ func (r *HttpRequest) RawSubPath(subPath string) *HttpRequest {
 	return r
 }
 
+// SetError sets error of request if there is no error yet.
+// All the following methods will be skipped and DoRaw will return the error.
+func (r *HttpRequest) SetError(err error) *HttpRequest {
+	if nil == r.err {
+		r.err = err
+	}
+
+	return r
+}
+
+// Client sets HTTP client to send request, http.DefaultClient is used by default
+func (r *HttpRequest) Client(c *http.Client) *HttpRequest {
+	if nil == r.err {
+		r.client = c
+	}
+
+	return r
+}
+
 // Set HTTP method
 func (r *HttpRequest) Method(m string) *HttpRequest {
 	if nil == r.err {
@@ -219,7 +240,12 @@ func (r *HttpRequest) DoRaw() (*http.Response, error) {
 
 	req.Header = r.headers
 
-	resp, err := http.DefaultClient.Do(req)
+	client := http.DefaultClient
+	if nil != r.client {
+		client = r.client
+	}
+
+	resp, err := client.Do(req)
 	if nil != err {
 		return nil, fmt.Errorf("Request remote error: %v", err)
 	}